@@ -0,0 +1,99 @@
+package configuration
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Logger gets warnings of the configuration reader, e.g. about deprecated keys
+type Logger func(warning string)
+
+type configWarning struct {
+	key     string
+	message string
+}
+
+// Move the values of the alias keys to the key of the field
+// the key wins if both the key and its alias are set in the same source
+func (cr *configReader) applyAliases(it intermediateTree, si []structInfo) {
+	for _, info := range si {
+		if info.deprecated && len(info.aliases) == 0 {
+			for _, d := range it[info.keyName] {
+				cr.warn(info.keyName, "key \""+info.keyName+"\" in \""+cr.sourceName(d.source)+"\" is deprecated")
+			}
+			continue
+		}
+
+		moved := false
+		for _, alias := range info.aliases {
+			for _, d := range it[alias] {
+				source := cr.sourceName(d.source)
+				if slices.ContainsFunc(it[info.keyName], func(data intermediateData) bool { return data.source == d.source }) {
+					cr.warn(info.keyName, "key \""+alias+"\" in \""+source+"\" is ignored, \""+info.keyName+"\" is set")
+					continue
+				}
+				if info.deprecated {
+					cr.warn(info.keyName, "key \""+alias+"\" in \""+source+"\" is deprecated, use \""+info.keyName+"\"")
+				}
+				it[info.keyName] = append(it[info.keyName], d)
+				moved = true
+			}
+			delete(it, alias)
+		}
+		if moved {
+			slices.SortStableFunc(it[info.keyName], func(a, b intermediateData) int {
+				return a.source - b.source
+			})
+		}
+	}
+}
+
+// Check that every alias belongs to one field and is not the key of a field
+func checkAliases(si []structInfo) error {
+	keys := map[string]string{} // key name - field which has it
+	for _, info := range si {
+		keys[info.keyName] = info.fieldName
+	}
+	aliases := map[string]string{} // alias - field which has it
+	for _, info := range si {
+		for _, alias := range info.aliases {
+			if field, ok := keys[alias]; ok {
+				return errors.New("alias \"" + alias + "\" of field " + info.fieldName + " is the key of field " + field)
+			} else if field, ok := aliases[alias]; ok && field != info.fieldName {
+				return errors.New("alias \"" + alias + "\" is in fields " + field + " and " + info.fieldName)
+			}
+			aliases[alias] = info.fieldName
+		}
+	}
+	return nil
+}
+
+func (cr *configReader) warn(key, message string) {
+	cr.data.warnings = append(cr.data.warnings, configWarning{key: key, message: message})
+	if cr.options.Logger != nil {
+		cr.options.Logger(message)
+	}
+}
+
+// Get the aliases of the key from "alias=name1|name2" option
+func getAliases(option string) []string {
+	aliases := []string{}
+	for _, alias := range strings.Split(option[len("alias="):], "|") {
+		alias = strings.Trim(alias, " .")
+		if alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
+func (info structInfo) hasName(name string) bool {
+	return info.keyName == name || slices.Contains(info.aliases, name)
+}
+
+// Whether the key or one of the aliases is under the prefix
+func (info structInfo) hasNamePrefix(prefix string) bool {
+	return strings.HasPrefix(info.keyName, prefix+".") ||
+		slices.ContainsFunc(info.aliases, func(alias string) bool { return strings.HasPrefix(alias, prefix+".") })
+}
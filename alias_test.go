@@ -0,0 +1,157 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReadConfig_alias_success(t *testing.T) {
+	// Arrange
+	config := &struct {
+		MaxConns int      `env:"max_conns,alias=maxconnections|pool.size"`
+		Hosts    []string `env:"hosts,alias=servers"`
+		Db       struct {
+			Name string `env:"name,alias=database"`
+		} `env:"db"`
+	}{}
+	base := "maxconnections = 10\nservers = a,b\ndb.database = d1"
+	site := `{"pool": {"size": 20}, "db": {"name": "d2"}}`
+
+	// Act
+	cr := NewConfigReader().
+		AddString(base, FtEnv, "base").
+		AddString(site, FtJson, "site").
+		RewriteValues(true).
+		Strict(true)
+	err := cr.ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, 20, config.MaxConns)
+	require.Equal(t, []string{"a", "b"}, config.Hosts)
+	require.Equal(t, "d2", config.Db.Name)
+	require.Nil(t, cr.Warnings())
+}
+
+func Test_ReadConfig_alias_both_set(t *testing.T) {
+	// Arrange
+	config := &struct {
+		MaxConns int `env:"max_conns,alias=maxconnections"`
+	}{}
+	warnings := []string{}
+
+	// Act
+	cr := NewConfigReader().
+		AddString("maxconnections = 10\nmax_conns = 5", FtEnv, "base").
+		WithLogger(func(warning string) { warnings = append(warnings, warning) })
+	err := cr.ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, 5, config.MaxConns)
+	require.Equal(t, []string{`key "maxconnections" in "base" is ignored, "max_conns" is set`}, warnings)
+	require.Equal(t, warnings, cr.Warnings())
+}
+
+func Test_ReadConfig_alias_deprecated(t *testing.T) {
+	// Arrange
+	config := &struct {
+		MaxConns int    `env:"max_conns,alias=maxconnections,deprecated"`
+		Timeout  string `env:"timeout,deprecated"`
+	}{}
+	t.Setenv("maxconnections", "7")
+
+	// Act
+	cr := NewConfigReader().
+		AddString("timeout = 1s", FtEnv, "base").
+		AddEnvironment()
+	err := cr.ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, 7, config.MaxConns)
+	require.Equal(t, "1s", config.Timeout)
+	require.Equal(t, []string{
+		`key "maxconnections" in "environment" is deprecated, use "max_conns"`,
+		`key "timeout" in "base" is deprecated`,
+	}, cr.Warnings())
+
+	explanation, err := cr.Explain(config)
+	require.Nil(t, err)
+	require.Equal(t, "environment", explanation[0].Source)
+	require.Equal(t, []string{`key "maxconnections" in "environment" is deprecated, use "max_conns"`}, explanation[0].Warnings)
+}
+
+type testCaseGetAliases struct {
+	option   string
+	expected []string
+}
+
+func Test_getAliases_cases(t *testing.T) {
+	// Arrange
+	cases := []testCaseGetAliases{
+		{"alias=a", []string{"a"}},
+		{"alias=a|b.c", []string{"a", "b.c"}},
+		{"alias= a | |b", []string{"a", "b"}},
+		{"alias=", []string{}},
+	}
+
+	for _, c := range cases {
+		test_getAliases_cases(t, c)
+	}
+}
+
+func test_getAliases_cases(t *testing.T, testCase testCaseGetAliases) {
+	// Act
+	aliases := getAliases(testCase.option)
+
+	// Assert
+	require.Equal(t, testCase.expected, aliases, testCase.option)
+}
+
+type testCaseAliasError struct {
+	config interface{}
+	err    string
+}
+
+func Test_ReadConfig_alias_error_cases(t *testing.T) {
+	// Arrange
+	cases := []testCaseAliasError{
+		{&struct {
+			A int `env:"a,alias=b"`
+			B int `env:"b"`
+		}{}, `alias "b" of field A is the key of field B`},
+		{&struct {
+			B int `env:"b"`
+			A int `env:"a,alias=b"`
+		}{}, `alias "b" of field A is the key of field B`},
+		{&struct {
+			A int `env:"a,alias=a"`
+		}{}, `alias "a" of field A is the key of field A`},
+		{&struct {
+			A int `env:"a,alias=c"`
+			B int `env:"b,alias=d|c"`
+		}{}, `alias "c" is in fields A and B`},
+		{&struct {
+			A   int `env:"a,alias=sub.b"`
+			Sub struct {
+				B int `env:"b"`
+			} `env:"sub"`
+		}{}, `alias "sub.b" of field A is the key of field Sub.B`},
+	}
+
+	// Act & Assert
+	for _, c := range cases {
+		t.Log("Test case:", c.err)
+		test_ReadConfig_alias_error(t, c)
+	}
+}
+
+func test_ReadConfig_alias_error(t *testing.T, testCase testCaseAliasError) {
+	// Act
+	err := NewConfigReader().AddString("a = 1", FtEnv, "test").ReadConfig(testCase.config)
+
+	// Assert
+	require.EqualError(t, err, testCase.err)
+}
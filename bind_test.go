@@ -0,0 +1,89 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCaseBindCache struct {
+	Size int    `env:"size,required"`
+	Mode string `env:"mode" def:"lru"`
+}
+
+type testCaseBindAuth struct {
+	Users []string `env:"users"`
+}
+
+func Test_ReadConfigAt_success(t *testing.T) {
+	// Arrange
+	cache := &testCaseBindCache{}
+	cr := NewConfigReader().AddString("[plugins.cache]\nsize = 10\n[other]\nsize = 1\n", FtIni, "test")
+
+	// Act
+	err := cr.ReadConfigAt("plugins.cache", cache)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, &testCaseBindCache{Size: 10, Mode: "lru"}, cache)
+}
+
+func Test_Bind_success(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Host string `env:"host"`
+	}{}
+	cache := &testCaseBindCache{}
+	auth := &testCaseBindAuth{}
+	cr := NewConfigReader().
+		AddString(`{"host": "h", "plugins": {"cache": {"size": 5, "mode": "lfu"}, "auth": {"users": ["a", "b"]}}}`, FtJson, "test").
+		Bind("plugins.cache", cache).
+		Bind("PLUGINS.AUTH.", auth).
+		Strict(true)
+
+	// Act
+	err := cr.ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "h", config.Host)
+	require.Equal(t, &testCaseBindCache{Size: 5, Mode: "lfu"}, cache)
+	require.Equal(t, []string{"a", "b"}, auth.Users)
+}
+
+func Test_Bind_required_error(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Host string `env:"host"`
+	}{}
+	cr := NewConfigReader().
+		AddString("host = h", FtEnv, "test").
+		Bind("plugins.cache", &testCaseBindCache{})
+
+	// Act
+	err := cr.ReadConfig(config)
+
+	// Assert
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "Size")
+}
+
+func Test_Strict_error_cases(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Host string `env:"host"`
+	}{}
+	cr := NewConfigReader().
+		AddString("host = h\nport = 1\n", FtEnv, "env").
+		AddString("{\n\"db\": {\"user\": \"u\"}\n}", FtJson, "json").
+		Strict(true)
+
+	// Act
+	err := cr.ReadConfig(config)
+	errNotStrict := cr.Strict(false).ReadConfig(config)
+
+	// Assert
+	require.NotNil(t, err)
+	require.Equal(t, "unknown key \"port\" in \"env\" (line 2)\nunknown key \"db\" in \"json\" (line 2)", err.Error())
+	require.Nil(t, errNotStrict)
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	goc "github.com/mansiper/goconfiguration"
+)
+
+const usage = `Usage:
+  goconf lint file...                         check syntax of the files
+  goconf convert in out                       convert the file to the format of the out file
+  goconf merge [options] [-format f] file...  print the merged keys of the files
+  goconf get [options] file... key            print the value of the key
+  goconf explain [options] file...            print the merged keys with their sources
+
+Options:
+  -append     append collections from all the files
+  -no-rewrite take the first value of the key instead of the last one
+Formats: env, ini, json
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	var err error
+	switch args[0] {
+	case "lint":
+		err = lint(args[1:], stdout)
+	case "convert":
+		err = convert(args[1:])
+	case "merge":
+		err = merge(args[1:], stdout)
+	case "get":
+		err = get(args[1:], stdout)
+	case "explain":
+		err = explain(args[1:], stdout)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		err = errors.New("unknown command " + args[0] + "\n" + usage)
+	}
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func lint(files []string, stdout io.Writer) error {
+	if len(files) == 0 {
+		return errors.New("no files to lint")
+	}
+
+	failed := 0
+	for _, file := range files {
+		if _, err := readTree(false, true, file); err != nil {
+			fmt.Fprintln(stdout, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files have errors", failed, len(files))
+	}
+	return nil
+}
+
+func convert(args []string) error {
+	if len(args) != 2 {
+		return errors.New("convert needs input and output files")
+	}
+
+	tree, err := readTree(false, true, args[0])
+	if err != nil {
+		return err
+	}
+	b, err := marshal(tree, strings.TrimPrefix(filepath.Ext(args[1]), "."))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(args[1], b, 0o644)
+}
+
+func merge(args []string, stdout io.Writer) error {
+	flags := newFlagSet("merge")
+	format := flags.String("format", "env", "output format")
+	appendCollections, noRewrite := mergeFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	tree, err := readTree(*appendCollections, !*noRewrite, flags.Args()...)
+	if err != nil {
+		return err
+	}
+	b, err := marshal(tree, *format)
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(b)
+	return err
+}
+
+func get(args []string, stdout io.Writer) error {
+	flags := newFlagSet("get")
+	appendCollections, noRewrite := mergeFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() < 2 {
+		return errors.New("get needs files and key")
+	}
+
+	files := flags.Args()[:flags.NArg()-1]
+	key := flags.Arg(flags.NArg() - 1)
+	tree, err := readTree(*appendCollections, !*noRewrite, files...)
+	if err != nil {
+		return err
+	}
+
+	value, ok := tree.Get(key)
+	if !ok {
+		return errors.New("key " + key + " not found")
+	}
+	switch value := value.(type) {
+	case string:
+		fmt.Fprintln(stdout, value)
+	case []string:
+		for _, v := range value {
+			fmt.Fprintln(stdout, v)
+		}
+	case map[string]string:
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintln(stdout, k+":"+value[k])
+		}
+	}
+	return nil
+}
+
+func explain(args []string, stdout io.Writer) error {
+	flags := newFlagSet("explain")
+	appendCollections, noRewrite := mergeFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	tree, err := readTree(*appendCollections, !*noRewrite, flags.Args()...)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(stdout, tree.Explain().String())
+	return nil
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	return flags
+}
+
+func mergeFlags(flags *flag.FlagSet) (*bool, *bool) {
+	appendCollections := flags.Bool("append", false, "append collections from all the files")
+	noRewrite := flags.Bool("no-rewrite", false, "take the first value of the key")
+	return appendCollections, noRewrite
+}
+
+func readTree(appendCollections, rewrite bool, files ...string) (*goc.Tree, error) {
+	if len(files) == 0 {
+		return nil, errors.New("no files to read")
+	}
+
+	cr := goc.NewConfigReader(files...).RewriteValues(rewrite)
+	if errs := cr.GetErrors(); errs != nil {
+		return nil, errors.Join(errs...)
+	}
+	if appendCollections {
+		return cr.ReadTree(goc.AppendCollections())
+	}
+	return cr.ReadTree()
+}
+
+func marshal(tree *goc.Tree, format string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "env":
+		return tree.Marshal(goc.FtEnv)
+	case "ini":
+		return tree.Marshal(goc.FtIni)
+	case "json":
+		return tree.Marshal(goc.FtJson)
+	}
+	return nil, errors.New("unsupported format " + format)
+}
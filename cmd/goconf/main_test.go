@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.Nil(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+type testCaseRun struct {
+	args   []string
+	code   int
+	stdout string
+	stderr string
+}
+
+func Test_run_cases(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	a := writeFile(t, dir, "a.ini", "host = h\nhosts[] = a\n[db]\nport = 80\n")
+	b := writeFile(t, dir, "b.json", `{"db": {"port": 81}, "hosts": ["b"]}`)
+	bad := writeFile(t, dir, "bad.env", "key = \"value\n")
+	cases := []testCaseRun{
+		{[]string{"lint", a, b}, 0, "", ""},
+		{[]string{"lint", a, bad}, 1, "error in file \"" + bad + "\": ", "1 of 2 files have errors"},
+		{[]string{"merge", a, b}, 0, "host = h\nhosts = b\ndb.port = 81\n", ""},
+		{[]string{"merge", "-format", "json", "-append", a, b}, 0, "{\n  \"host\": \"h\",\n  \"hosts\": [\"a\", \"b\"],\n  \"db\": {\n    \"port\": 81\n  }\n}\n", ""},
+		{[]string{"get", "-no-rewrite", a, b, "db.port"}, 0, "80\n", ""},
+		{[]string{"get", a, "unknown"}, 1, "", "key unknown not found"},
+		{[]string{"explain", a}, 0, "db.port", ""},
+		{[]string{"unknown"}, 1, "", "unknown command unknown"},
+		{[]string{}, 2, "", "Usage:"},
+	}
+
+	// Act & Assert
+	for i, c := range cases {
+		t.Log("Test case:", i)
+		test_run(t, c)
+	}
+}
+
+func test_run(t *testing.T, testCase testCaseRun) {
+	// Arrange
+	var stdout, stderr bytes.Buffer
+
+	// Act
+	code := run(testCase.args, &stdout, &stderr)
+
+	// Assert
+	require.Equal(t, testCase.code, code, stderr.String())
+	require.Contains(t, stdout.String(), testCase.stdout)
+	require.Contains(t, stderr.String(), testCase.stderr)
+}
+
+func Test_run_convert(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	in := writeFile(t, dir, "in.env", "port = 80\ndb.user = u\n")
+	out := filepath.Join(dir, "out.ini")
+	var stdout, stderr bytes.Buffer
+
+	// Act
+	code := run([]string{"convert", in, out}, &stdout, &stderr)
+
+	// Assert
+	require.Equal(t, 0, code, stderr.String())
+	b, err := os.ReadFile(out)
+	require.Nil(t, err)
+	require.Equal(t, "port = 80\n\n[db]\nuser = u\n", string(b))
+}
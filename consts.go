@@ -7,6 +7,16 @@ const (
 	FtIni
 	FtJson
 	// FtYaml
+	ftKeyPerFile
+	ftStruct
+)
+
+const (
+	NamingLower     namingType = iota // MaxIdleConns is "maxidleconns"
+	NamingSnakeCase                   // MaxIdleConns is "max_idle_conns"
+	NamingKebabCase                   // MaxIdleConns is "max-idle-conns"
+	NamingCamelCase                   // MaxIdleConns is "maxIdleConns"
+	NamingExact                       // MaxIdleConns is "MaxIdleConns"
 )
 
 const (
@@ -15,6 +25,23 @@ const (
 	ignoreField = "-"
 	nilDefault  = "*nil"
 	nowTime     = "now"
+	remainKey   = "*" // key name of the field with "remain" option
+
+	unsetValue      = "*unset"  // removes the key set by earlier sources
+	unsetItemPrefix = "*unset:" // removes the slice item set by earlier sources
+)
+
+const (
+	secretFilePrefix  = "file:"
+	secretFileMaxSize = 64 * 1024
+	redactedValue     = "******"
+	redactedHashLen   = 8
+)
+
+const (
+	envComment  = "#"
+	iniComment  = ";"
+	jsonComment = "//"
 )
 
 const (
@@ -24,4 +51,5 @@ const (
 	vtNumber
 	vtBool
 	vtNull
+	vtUnset // the whole collection is removed by "*unset"
 )
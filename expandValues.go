@@ -0,0 +1,98 @@
+package configuration
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strings"
+)
+
+const (
+	expandMark    = '$'
+	expandOpener  = "${"
+	expandCloser  = "}"
+	expandEscaped = "$$"
+)
+
+// Expand ${name} references in a value
+// name is looked up among config keys first (effective value or its default), then among environment variables
+// "$$" is a literal "$", a single "$" not followed by "{" is kept as is
+func (cr *configReader) expandValue(value string, it intermediateTree, si []structInfo, visited []string) (string, error) {
+	if !strings.ContainsRune(value, expandMark) {
+		return value, nil
+	}
+
+	var result strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != expandMark {
+			result.WriteByte(value[i])
+			continue
+		}
+		if strings.HasPrefix(value[i:], expandEscaped) {
+			result.WriteRune(expandMark)
+			i++
+			continue
+		}
+		if !strings.HasPrefix(value[i:], expandOpener) {
+			result.WriteRune(expandMark)
+			continue
+		}
+
+		end := strings.Index(value[i+len(expandOpener):], expandCloser)
+		if end == -1 {
+			return "", errors.New("unclosed variable reference in \"" + value + "\"")
+		}
+		name := strings.TrimSpace(value[i+len(expandOpener) : i+len(expandOpener)+end])
+		if name == "" {
+			return "", errors.New("empty variable reference in \"" + value + "\"")
+		}
+
+		resolved, err := cr.resolveVariable(name, it, si, visited)
+		if err != nil {
+			return "", err
+		}
+		result.WriteString(resolved)
+		i += len(expandOpener) + end
+	}
+
+	return result.String(), nil
+}
+
+func (cr *configReader) resolveVariable(name string, it intermediateTree, si []structInfo, visited []string) (string, error) {
+	keyName := cr.keyCase(name)
+	for _, s := range si {
+		if s.keyName != keyName {
+			continue
+		}
+		if slices.Contains(visited, keyName) {
+			return "", errors.New("cyclic variable reference ${" + name + "}")
+		}
+		if s.isMap {
+			return "", errors.New("variable ${" + name + "} references a map")
+		}
+
+		if data, ok := it[keyName]; ok && len(data) > 0 {
+			d := data[len(data)-1]
+			if !cr.options.RewriteValues && !s.isSlice {
+				d = data[0]
+			}
+			if s.isSlice {
+				if values, vType := mergeSliceData(s, data); len(values) > 0 && vType != vtNull {
+					return strings.Join(values, s.separator), nil
+				}
+			} else if value, ok := d.value.(string); ok && value != "" && d.valueType != vtNull {
+				return value, nil
+			}
+		}
+
+		if s.defValue == nilDefault {
+			return "", nil
+		}
+		return cr.expandValue(s.defValue, it, si, append(slices.Clone(visited), keyName))
+	}
+
+	if value, ok := os.LookupEnv(name); ok {
+		return value, nil
+	}
+	return "", errors.New("unknown variable ${" + name + "}")
+}
@@ -0,0 +1,110 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCaseExpandValue struct {
+	value    string
+	expected string
+}
+
+func Test_expandValue_success_cases(t *testing.T) {
+	// Arrange
+	t.Setenv("GOCONF_TEST_HOME", "/home/user")
+	cases := []testCaseExpandValue{
+		{"value", "value"},
+		{"${GOCONF_TEST_HOME}/.cache", "/home/user/.cache"},
+		{"${db.host}:5432", "localhost:5432"},
+		{"${db.url}", "localhost:5432/db"},
+		{"${db.hosts}", "a;b"},
+		{"$$HOME", "$HOME"},
+		{"100$", "100$"},
+		{"$name", "$name"},
+		{"${ db.host }", "localhost"},
+	}
+
+	// Act & Assert
+	for _, c := range cases {
+		t.Log("Test case:", c.value)
+		test_expandValue_success(t, c)
+	}
+}
+
+func test_expandValue_success(t *testing.T, testCase testCaseExpandValue) {
+	// Arrange
+	cr := &configReader{options: ConfigOptions{RewriteValues: true}}
+	si := []structInfo{
+		{keyName: "db.host", defValue: "127.0.0.1"},
+		{keyName: "db.url", defValue: "${db.host}:5432/db"},
+		{keyName: "db.hosts", isSlice: true, separator: ";"},
+	}
+	it := intermediateTree{
+		"db.host":  []intermediateData{{value: "localhost", source: 0, valueType: vtAny}},
+		"db.hosts": []intermediateData{{value: []string{"a", "b"}, source: 0, valueType: vtAny}},
+	}
+
+	// Act
+	result, err := cr.expandValue(testCase.value, it, si, nil)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, testCase.expected, result)
+}
+
+func Test_expandValue_error_cases(t *testing.T) {
+	// Arrange
+	cases := []testCaseExpandValue{
+		{"${unclosed", "unclosed variable reference in \"${unclosed\""},
+		{"${}", "empty variable reference in \"${}\""},
+		{"${GOCONF_TEST_UNKNOWN}", "unknown variable ${GOCONF_TEST_UNKNOWN}"},
+		{"${a}", "cyclic variable reference ${a}"},
+		{"${m}", "variable ${m} references a map"},
+	}
+
+	// Act & Assert
+	for _, c := range cases {
+		t.Log("Test case:", c.value)
+		test_expandValue_error(t, c)
+	}
+}
+
+func test_expandValue_error(t *testing.T, testCase testCaseExpandValue) {
+	// Arrange
+	cr := &configReader{options: ConfigOptions{RewriteValues: true}}
+	si := []structInfo{
+		{keyName: "a", defValue: "${b}"},
+		{keyName: "b", defValue: "${a}"},
+		{keyName: "m", isMap: true},
+	}
+
+	// Act
+	_, err := cr.expandValue(testCase.value, intermediateTree{}, si, nil)
+
+	// Assert
+	require.NotNil(t, err)
+	require.Equal(t, testCase.expected, err.Error())
+}
+
+func Test_setValues_expandDefault(t *testing.T) {
+	// Arrange
+	t.Setenv("GOCONF_TEST_HOME", "/home/user")
+	config := &struct {
+		Cache string `env:"cache" def:"${GOCONF_TEST_HOME}/.cache/app"`
+		Host  string `env:"host" def:"localhost"`
+		Addr  string `env:"addr" def:"${host}:5432"`
+		Price string `env:"price" def:"$$10"`
+	}{}
+	cr := NewConfigReader().AddString("host = db", FtEnv, "test")
+
+	// Act
+	err := cr.ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "/home/user/.cache/app", config.Cache)
+	require.Equal(t, "db:5432", config.Addr)
+	require.Equal(t, "$10", config.Price)
+}
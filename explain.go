@@ -0,0 +1,182 @@
+package configuration
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	explainDefaultSource = "default"
+	explainNoSource      = "-"
+	explainEnvironment   = "environment"
+)
+
+// Information about a single configuration value
+type ExplainEntry struct {
+	// Key name in configuration sources
+	Key string `json:"key"`
+	// Go field path in the user config struct
+	Field string `json:"field"`
+	// Go type of the field
+	Type string `json:"type"`
+	// Effective value
+	Value string `json:"value"`
+	// Value from the "def" tag
+	Default string `json:"default,omitempty"`
+	// Whether the field is required
+	Required bool `json:"required"`
+	// Name of the source the value was taken from
+	Source string `json:"source"`
+	// Line in the source, 0 if unknown
+	Line int `json:"line,omitempty"`
+	// Reference the value was resolved from, e.g. secret file
+	Reference string `json:"reference,omitempty"`
+	// Warnings about the key, e.g. deprecated aliases
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Effective configuration as a list of entries
+type Explanation []ExplainEntry
+
+// Explain where the values of the user config came from
+// must be called after ReadConfig
+// userConfig - pointer to the user config struct
+func (cr *configReader) Explain(userConfig interface{}) (Explanation, error) {
+	if cr.data.lastTree == nil {
+		return nil, errors.New("configuration has not been read yet")
+	}
+
+	si, err := cr.getStructInfo(userConfig, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(Explanation, 0, len(si))
+	for _, info := range si {
+		entry := ExplainEntry{
+			Key:      info.keyName,
+			Field:    info.fieldName,
+			Type:     info.field.Type().String(),
+			Value:    cr.redactIfSecret(info, formatFieldValue(info)),
+			Required: info.isRequired,
+			Source:   explainNoSource,
+		}
+		if info.defValue != "" && info.defValue != nilDefault {
+			entry.Default = cr.redactIfSecret(info, info.defValue)
+		}
+
+		data := cr.effectiveData(info, cr.data.lastTree[info.keyName])
+		if len(data) > 0 {
+			names := make([]string, 0, len(data))
+			for _, d := range data {
+				names = append(names, cr.sourceName(d.source))
+			}
+			entry.Source = strings.Join(names, ", ")
+			entry.Line = data[len(data)-1].line
+			entry.Reference = data[len(data)-1].ref
+		} else if entry.Default != "" {
+			entry.Source = explainDefaultSource
+		}
+		for _, w := range cr.data.warnings {
+			if w.key == info.keyName {
+				entry.Warnings = append(entry.Warnings, w.message)
+			}
+		}
+
+		result = append(result, entry)
+	}
+
+	return result, nil
+}
+
+// Get intermediate data which the field value was taken from
+func (cr *configReader) effectiveData(info structInfo, data []intermediateData) []intermediateData {
+	nonEmpty := make([]intermediateData, 0, len(data))
+	for _, d := range data {
+		switch value := d.value.(type) {
+		case string:
+			if value != "" && d.valueType != vtNull {
+				nonEmpty = append(nonEmpty, d)
+			}
+		case []string:
+			if len(value) > 0 && !(len(value) == 1 && value[0] == "") {
+				nonEmpty = append(nonEmpty, d)
+			}
+		case map[string]string:
+			if len(value) > 0 {
+				nonEmpty = append(nonEmpty, d)
+			}
+		case *rawValue, indexItems:
+			nonEmpty = append(nonEmpty, d)
+		}
+	}
+	if len(nonEmpty) == 0 {
+		return nil
+	}
+
+	if (info.isSlice || info.isMap) && info.mergesAll() {
+		return nonEmpty
+	} else if (info.isSlice || info.isMap) && info.merge == mergeFirst {
+		return nonEmpty[:1]
+	} else if !info.isSlice && !info.isMap && !cr.options.RewriteValues {
+		return nonEmpty[:1]
+	}
+
+	// items set by index after the last slice are applied to it
+	last := len(nonEmpty) - 1
+	for last > 0 {
+		if _, ok := nonEmpty[last].value.(indexItems); !ok {
+			break
+		}
+		last--
+	}
+	return nonEmpty[last:]
+}
+
+func (cr *configReader) sourceName(sourceId int) string {
+	if sourceId < 0 || sourceId >= len(cr.data.lastSources) {
+		return explainNoSource
+	}
+
+	source := cr.data.lastSources[sourceId]
+	name := source.name
+	if source.ft == ftEnvironment {
+		name = explainEnvironment
+	} else if source.fromFile || source.ft == ftKeyPerFile {
+		name = source.value
+	}
+	if source.layer != "" {
+		return source.layer + ": " + name
+	}
+	return name
+}
+
+// Get the explanation as a table
+func (e Explanation) String() string {
+	var buffer bytes.Buffer
+	w := tabwriter.NewWriter(&buffer, 0, 4, 2, ' ', 0)
+	w.Write([]byte("KEY\tFIELD\tTYPE\tVALUE\tDEFAULT\tREQUIRED\tSOURCE\n"))
+	for _, entry := range e {
+		source := entry.Source
+		if entry.Line > 0 {
+			source += ":" + strconv.Itoa(entry.Line)
+		}
+		if entry.Reference != "" {
+			source += " (" + entry.Reference + ")"
+		}
+		value := strings.ReplaceAll(entry.Value, "\n", "\\n")
+		w.Write([]byte(entry.Key + "\t" + entry.Field + "\t" + entry.Type + "\t" + value + "\t" +
+			entry.Default + "\t" + strconv.FormatBool(entry.Required) + "\t" + source + "\n"))
+	}
+	w.Flush()
+	return buffer.String()
+}
+
+// Get the explanation as a json array
+func (e Explanation) JSON() ([]byte, error) {
+	return json.MarshalIndent(e, "", "  ")
+}
@@ -0,0 +1,68 @@
+package configuration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCaseExplainConfig struct {
+	Host     string         `env:"host" def:"localhost"`
+	Port     int            `env:"port,required"`
+	Password string         `env:"password,secret" def:"default"`
+	Hosts    []string       `env:"hosts,append"`
+	Limits   map[string]int `env:"limits"`
+	Db       struct {
+		Name *string `env:"name"`
+	} `env:"db"`
+}
+
+func Test_Explain_success(t *testing.T) {
+	// Arrange
+	config := &testCaseExplainConfig{}
+	cr := NewConfigReader().
+		AddString("port = 80\nhosts = a\npassword = s3cr3t", FtEnv, "first").
+		AddString("hosts[] = b\n\nlimits[a] = 1", FtIni, "second")
+
+	// Act
+	err := cr.ReadConfig(config)
+	require.Nil(t, err)
+	result, err := cr.Explain(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Len(t, result, 6)
+	require.Equal(t, ExplainEntry{Key: "host", Field: "Host", Type: "string", Value: "localhost",
+		Default: "localhost", Source: explainDefaultSource}, result[0])
+	require.Equal(t, ExplainEntry{Key: "port", Field: "Port", Type: "int", Value: "80",
+		Required: true, Source: "first", Line: 1}, result[1])
+	require.Equal(t, ExplainEntry{Key: "password", Field: "Password", Type: "string", Value: redactedValue,
+		Default: redactedValue, Source: "first", Line: 3}, result[2])
+	require.Equal(t, ExplainEntry{Key: "hosts", Field: "Hosts", Type: "[]string", Value: "a,b",
+		Source: "first, second", Line: 1}, result[3])
+	require.Equal(t, ExplainEntry{Key: "limits", Field: "Limits", Type: "map[string]int", Value: "a:1",
+		Source: "second", Line: 3}, result[4])
+	require.Equal(t, ExplainEntry{Key: "db.name", Field: "Db.Name", Type: "*string", Value: nilDefault,
+		Source: explainNoSource}, result[5])
+	require.NotContains(t, result.String(), "s3cr3t")
+	require.Contains(t, result.String(), "first:1")
+
+	js, err := result.JSON()
+	require.Nil(t, err)
+	var parsed []map[string]interface{}
+	require.Nil(t, json.Unmarshal(js, &parsed))
+	require.Equal(t, "port", parsed[1]["key"])
+}
+
+func Test_Explain_notRead(t *testing.T) {
+	// Arrange
+	config := &testCaseExplainConfig{}
+	cr := NewConfigReader()
+
+	// Act
+	_, err := cr.Explain(config)
+
+	// Assert
+	require.NotNil(t, err)
+}
@@ -0,0 +1,100 @@
+package configuration
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Convert single value to the string which can be read back
+func formatScalar(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nilDefault
+		}
+		v = v.Elem()
+	}
+
+	switch value := v.Interface().(type) {
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case time.Duration:
+		return value.String()
+	case encoding.TextMarshaler:
+		if text, err := value.MarshalText(); err == nil {
+			return string(text)
+		}
+	case fmt.Stringer:
+		return value.String()
+	}
+
+	switch v.Kind() {
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case reflect.String:
+		return v.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// Convert slice or array field value to strings
+func formatSlice(v reflect.Value) []string {
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	result := make([]string, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		result[i] = formatScalar(v.Index(i))
+	}
+	return result
+}
+
+// Convert map field value to strings
+func formatMap(v reflect.Value) map[string]string {
+	result := make(map[string]string, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		result[iter.Key().String()] = formatScalar(iter.Value())
+	}
+	return result
+}
+
+func sortedMapKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Convert field value to the string as it would be written in the "def" tag
+func formatFieldValue(info structInfo) string {
+	v := info.field
+	if info.isRaw {
+		data, _ := rawFieldData(info)
+		return string(data)
+	} else if info.isSlice {
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nilDefault
+		}
+		return strings.Join(formatSlice(v), info.separator)
+	} else if info.isMap {
+		if v.IsNil() {
+			return nilDefault
+		}
+		m := formatMap(v)
+		items := make([]string, 0, len(m))
+		for _, k := range sortedMapKeys(m) {
+			items = append(items, k+info.separator2+m[k])
+		}
+		return strings.Join(items, info.separator)
+	}
+	return formatScalar(v)
+}
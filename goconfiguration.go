@@ -32,7 +32,8 @@ func NewConfigReader(files ...string) *configReader {
 // Add configuration file
 // file - relative or absolute path to the file
 // supported file types: json, ini, env
-func (cr *configReader) AddFile(file string) *configReader {
+// options - source options, e.g. WithPriority and WithLayer
+func (cr *configReader) AddFile(file string, options ...SourceOption) *configReader {
 	fileType := cr.getFileType(file)
 	if fileType == ftUnknown {
 		cr.data.initErrors = append(cr.data.initErrors, unsupportedFileTypeError(file))
@@ -44,33 +45,60 @@ func (cr *configReader) AddFile(file string) *configReader {
 		ft:       fileType,
 		fromFile: true,
 	}
-	cr.sources = append(cr.sources, source)
-	return cr
+	return cr.addSource(source, options)
 }
 
 // Use environment variables as a configuration source
-func (cr *configReader) AddEnvironment() *configReader {
+// options - source options, e.g. WithPriority and WithLayer
+func (cr *configReader) AddEnvironment(options ...SourceOption) *configReader {
 	source := configSource{
 		value:    "",
 		ft:       ftEnvironment,
 		fromFile: false,
 	}
-	cr.sources = append(cr.sources, source)
-	return cr
+	return cr.addSource(source, options)
+}
+
+// Use directory with one file per key as a configuration source (e.g. mounted Kubernetes ConfigMap)
+// dir - relative or absolute path to the directory
+// withSubdirs - read subdirectories, their names are used as key prefixes
+// options - source options, e.g. WithPriority and WithLayer
+func (cr *configReader) AddKeyPerFileDir(dir string, withSubdirs bool, options ...SourceOption) *configReader {
+	source := configSource{
+		value:       dir,
+		ft:          ftKeyPerFile,
+		fromFile:    false,
+		withSubdirs: withSubdirs,
+	}
+	return cr.addSource(source, options)
 }
 
 // Add configuration as a string
 // values - configuration string
 // formatType - format of the configuration string
-func (cr *configReader) AddString(values string, formatType formatType, name string) *configReader {
+// name - name of the source in errors and explanations
+// options - source options, e.g. WithPriority and WithLayer
+func (cr *configReader) AddString(values string, formatType formatType, name string, options ...SourceOption) *configReader {
 	source := configSource{
 		name:     name,
 		value:    values,
 		ft:       formatType,
 		fromFile: false,
 	}
-	cr.sources = append(cr.sources, source)
-	return cr
+	return cr.addSource(source, options)
+}
+
+// Add the values of the struct as a configuration source, e.g. defaults built in Go code
+// fields with zero values are skipped and don't override the earlier sources
+// config - struct or pointer to the struct with the same env tags as the user config struct
+// options - source options, e.g. WithPriority and WithLayer
+func (cr *configReader) AddStruct(config interface{}, options ...SourceOption) *configReader {
+	source, err := newStructSource(config)
+	if err != nil {
+		cr.data.initErrors = append(cr.data.initErrors, err.Error())
+		return cr
+	}
+	return cr.addSource(source, options)
 }
 
 // Set configuration reader options
@@ -84,6 +112,21 @@ func (cr *configReader) WithOptions(options ConfigOptions) *configReader {
 	return cr
 }
 
+// Register the struct to be filled from the keys under the prefix by ReadConfig and ReadConfigAt
+// prefix - key prefix, e.g. "plugins.cache", the root if empty
+// target - pointer to the struct
+func (cr *configReader) Bind(prefix string, target interface{}) *configReader {
+	cr.bindings = append(cr.bindings, binding{prefix: prefix, target: target})
+	return cr
+}
+
+// Set whether to return an error for keys which no struct field claims
+// strict - fail on unknown keys or not
+func (cr *configReader) Strict(strict bool) *configReader {
+	cr.options.Strict = strict
+	return cr
+}
+
 // Set whether to rewrite values (not for slice values)
 // rewrite - rewrite values or not
 func (cr *configReader) RewriteValues(rewrite bool) *configReader {
@@ -91,6 +134,43 @@ func (cr *configReader) RewriteValues(rewrite bool) *configReader {
 	return cr
 }
 
+// Set how key names of fields and sub-structures without env tag are made from Go field names
+// NamingCamelCase and NamingExact behave like NamingLower unless CaseSensitive(true) is set
+// naming - NamingLower (default), NamingSnakeCase, NamingKebabCase, NamingCamelCase or NamingExact
+func (cr *configReader) WithNaming(naming namingType) *configReader {
+	cr.options.Naming = naming
+	return cr
+}
+
+// Set whether to use json and yaml tags of fields without env tag
+// fallback - use json and yaml tags or not
+func (cr *configReader) FallbackTags(fallback bool) *configReader {
+	cr.options.FallbackTags = fallback
+	return cr
+}
+
+// Set whether to match key names, map keys and environment variables case sensitively
+// sensitive - keep the case of the keys or lowercase them
+func (cr *configReader) CaseSensitive(sensitive bool) *configReader {
+	cr.options.CaseSensitive = sensitive
+	return cr
+}
+
+// Set whether to keep the values of the user config struct set before reading
+// they are read as the source with the lowest priority, fields with zero values get values from sources and defaults
+// preserve - keep the values or not
+func (cr *configReader) PreserveValues(preserve bool) *configReader {
+	cr.options.PreserveValues = preserve
+	return cr
+}
+
+// Set whether to refuse world-readable secret files
+// strict - refuse world-readable secret files or not
+func (cr *configReader) StrictSecretFiles(strict bool) *configReader {
+	cr.options.StrictSecretFiles = strict
+	return cr
+}
+
 // Add custom parser for user types
 // fieldName - field name
 // parser - custom parser for user type
@@ -102,6 +182,13 @@ func (cr *configReader) WithParser(envName string, parser Parser) *configReader
 	return cr
 }
 
+// Set the logger for warnings, e.g. about deprecated keys
+// logger - function which gets the warning messages
+func (cr *configReader) WithLogger(logger Logger) *configReader {
+	cr.options.Logger = logger
+	return cr
+}
+
 // Ensure that there are no errors during configuration reading
 // panic if there are errors
 func (cr *configReader) EnsureHasNoErrors() *configReader {
@@ -123,30 +210,66 @@ func (cr *configReader) GetErrors() []error {
 	return nil
 }
 
+// Get warnings of the last configuration reading, e.g. about deprecated keys
+func (cr *configReader) Warnings() []string {
+	if len(cr.data.warnings) == 0 {
+		return nil
+	}
+	warnings := make([]string, len(cr.data.warnings))
+	for i, w := range cr.data.warnings {
+		warnings[i] = w.message
+	}
+	return warnings
+}
+
 // Read configuration into the user config struct
 // userConfig - pointer to the user config struct
 func (cr *configReader) ReadConfig(userConfig interface{}) error {
-	si, err := cr.getStructInfo(userConfig, "", "")
+	return cr.ReadConfigAt("", userConfig)
+}
+
+// Read configuration under the key prefix into the user config struct
+// structs registered by Bind are filled in the same pass
+// prefix - key prefix, e.g. "plugins.cache", the root if empty
+// userConfig - pointer to the user config struct
+func (cr *configReader) ReadConfigAt(prefix string, userConfig interface{}) error {
+	si, err := cr.getStructInfo(userConfig, "", keyPrefix(prefix))
 	if err != nil {
 		return err
 	}
+	for _, b := range cr.bindings {
+		bound, err := cr.getStructInfo(b.target, "", keyPrefix(b.prefix))
+		if err != nil {
+			return errors.New("can't bind \"" + b.prefix + "\": " + err.Error())
+		}
+		si = append(si, bound...)
+	}
+
+	if cr.options.PreserveValues {
+		sources := cr.sources
+		defer func() { cr.sources = sources }()
+		cr.sources = append(cr.preservedSources(prefix, userConfig), sources...)
+	}
 
 	it := intermediateTree{}
+	cr.data.trackUnknownKeys = cr.options.Strict
+	cr.data.unknownKeys = nil
+	err = cr.readSources(it, si)
+	if err != nil {
+		return err
+	}
+	if err = cr.unknownKeysError(); err != nil {
+		return err
+	}
 
-	for i, source := range cr.sources {
-		var err error = nil
-		if source.ft == ftEnvironment {
-			cr.readEnvironment(it, si, i)
-		} else if source.fromFile {
-			err = cr.readConfigFile(source, it, si, i)
-		} else {
-			err = cr.readConfigString(source, it, si, i)
-		}
-		if err != nil {
-			return err
-		}
+	err = cr.resolveSecretFiles(it, si)
+	if err != nil {
+		return err
 	}
 
+	cr.data.lastTree = it
+	cr.data.lastSources = cr.orderedSources()
+
 	err = cr.setValues(it, si)
 	if err != nil {
 		return err
@@ -174,3 +174,75 @@ func test_GetErrors_success(t *testing.T, errs []string) {
 		require.Equal(t, e, result[i].Error())
 	}
 }
+
+func Test_ReadConfig_case_sensitive(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Port    int               `env:"Port"`
+		LowPort int               `env:"port"`
+		Labels  map[string]string `env:"Labels" merge:"deep"`
+		Db      struct {
+			Host string `env:"Host"`
+			Name string
+		} `env:"Db"`
+		Tags []string `env:"Tags"`
+	}{}
+	data := `{"Port": 1, "port": 2, "Labels": {"A": "1", "a": "2"}, "Db": {"Host": "h", "name": "n"}}`
+	t.Setenv("Tags", "x,y")
+	t.Setenv("Tags_1", "z")
+	t.Setenv("TAGS", "w")
+
+	// Act
+	err := NewConfigReader().
+		AddString(data, FtJson, "json").
+		AddString("Labels[B] = 3", FtIni, "ini").
+		AddEnvironment().
+		CaseSensitive(true).
+		Strict(true).
+		ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, 1, config.Port)
+	require.Equal(t, 2, config.LowPort)
+	require.Equal(t, map[string]string{"A": "1", "a": "2", "B": "3"}, config.Labels)
+	require.Equal(t, "h", config.Db.Host)
+	require.Equal(t, "n", config.Db.Name)
+	require.Equal(t, []string{"x", "z"}, config.Tags)
+}
+
+func Test_ReadConfig_case_insensitive_duplicate(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Port int `env:"port"`
+	}{}
+
+	// Act
+	err := NewConfigReader().AddString(`{"Port": 1, "port": 2}`, FtJson, "json").ReadConfig(config)
+
+	// Assert
+	require.NotNil(t, err)
+}
+
+func Test_ReadTree_case_sensitive(t *testing.T) {
+	// Arrange
+	cr := NewConfigReader().AddString("Host = a\nhost = b", FtEnv, "env").CaseSensitive(true)
+
+	// Act
+	tree, err := cr.ReadTree()
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, []string{"Host", "host"}, tree.Keys(""))
+	host, _ := tree.GetString("Host")
+	require.Equal(t, "a", host)
+	_, ok := tree.Get("HOST")
+	require.False(t, ok)
+}
+
+func Test_formatType_values(t *testing.T) {
+	// Assert
+	require.Equal(t, formatType(2), FtEnv)
+	require.Equal(t, formatType(3), FtIni)
+	require.Equal(t, formatType(4), FtJson)
+}
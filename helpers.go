@@ -28,20 +28,46 @@ func (cr *configReader) currentPointInfo() string {
 	return "(" + strconv.Itoa(cr.data.currentLine) + ":" + strconv.Itoa(cr.data.currentPos) + ")"
 }
 
+// Split "name[]" and "name[key]" into the name, the key and whether it's a slice item
+func splitCollectionName(name string) (string, string, bool) {
+	isSlice := strings.HasSuffix(name, "[]")
+	if isSlice {
+		return name[:len(name)-2], "", true
+	}
+	openIndex := strings.Index(name, "[")
+	isMap := len(name) >= 4 && openIndex > 0 && openIndex < len(name)-2 && strings.HasSuffix(name, "]")
+	if isMap {
+		return name[:openIndex], name[openIndex+1 : len(name)-1], false
+	}
+	return name, "", false
+}
+
+func trimTrailingNewLine(value string) string {
+	value = strings.TrimSuffix(value, "\n")
+	return strings.TrimSuffix(value, "\r")
+}
+
 func unsupportedFileTypeError(fileName string) string {
 	return "unsupported file type: " + fileName
 }
 
-func addValue(structInfo structInfo, it intermediateTree, name, value, key string, isSlice bool, sourceId int) {
+func (cr *configReader) addValue(structInfo structInfo, it intermediateTree, name, value, key string, isSlice bool, sourceId int) error {
 	value = strings.Trim(value, " \t")
-	if structInfo.isSlice {
-		if _, ok := it[name]; !ok {
-			it[name] = []intermediateData{{source: sourceId, value: []string{}, valueType: vtAny}}
-		} else if !slices.ContainsFunc(it[name], func(data intermediateData) bool { return data.source == sourceId }) {
-			it[name] = append(it[name], intermediateData{source: sourceId, value: []string{}, valueType: vtAny})
+	if structInfo.isRemain {
+		if key != "" {
+			name += "." + key
+		}
+		cr.addRemainValue(structInfo, it, name, value, vtAny, isSlice, sourceId)
+	} else if structInfo.isRaw {
+		cr.addRawValue(structInfo, it, name, value, key, isSlice, sourceId)
+	} else if structInfo.isSlice && key != "" {
+		return cr.addIndexValue(structInfo, it, name, value, key, vtAny, sourceId)
+	} else if structInfo.isSlice {
+		if !slices.ContainsFunc(it[name], func(data intermediateData) bool { return isSourceSlice(data, sourceId) }) {
+			it[name] = append(it[name], intermediateData{source: sourceId, value: []string{}, valueType: vtAny, line: cr.data.keyLine})
 		}
 		for i, v := range it[name] {
-			if v.source == sourceId {
+			if isSourceSlice(v, sourceId) {
 				if isSlice {
 					it[name][i].value = append(it[name][i].value.([]string), value)
 				} else {
@@ -50,37 +76,79 @@ func addValue(structInfo structInfo, it intermediateTree, name, value, key strin
 				break
 			}
 		}
-	} else if structInfo.isMap && key != "" {
+	} else if structInfo.isMap {
+		items := map[string]string{}
+		vType := vtAny
+		if key != "" {
+			items[key] = value
+		} else if value == unsetValue {
+			vType = vtUnset
+		} else if value == nilDefault {
+			vType = vtNull
+		} else if value != "" {
+			for _, item := range strings.Split(value, structInfo.separator) {
+				kv := strings.SplitN(item, structInfo.separator2, 2)
+				if len(kv) != 2 {
+					return errors.New("invalid map value for key \"" + name + "\" " + cr.currentPointInfo())
+				}
+				items[strings.Trim(kv[0], " \t")] = strings.Trim(kv[1], " \t")
+			}
+		}
 		if _, ok := it[name]; !ok {
-			it[name] = []intermediateData{{source: sourceId, value: map[string]string{}, valueType: vtAny}}
+			it[name] = []intermediateData{{source: sourceId, value: map[string]string{}, valueType: vtAny, line: cr.data.keyLine}}
 		} else if !slices.ContainsFunc(it[name], func(data intermediateData) bool { return data.source == sourceId }) {
-			it[name] = append(it[name], intermediateData{source: sourceId, value: map[string]string{}, valueType: vtAny})
+			it[name] = append(it[name], intermediateData{source: sourceId, value: map[string]string{}, valueType: vtAny, line: cr.data.keyLine})
 		}
 		for i, v := range it[name] {
 			if v.source == sourceId {
-				it[name][i].value.(map[string]string)[key] = value
+				for k, item := range items {
+					it[name][i].value.(map[string]string)[k] = item
+				}
+				if vType == vtUnset || vType == vtNull {
+					it[name][i].valueType = vType
+				}
 			}
 		}
 	} else {
 		if _, ok := it[name]; !ok {
-			it[name] = []intermediateData{{source: sourceId, value: value, valueType: vtAny}}
+			it[name] = []intermediateData{{source: sourceId, value: value, valueType: vtAny, line: cr.data.keyLine}}
 		} else {
-			it[name] = append(it[name], intermediateData{source: sourceId, value: value, valueType: vtAny})
+			it[name] = append(it[name], intermediateData{source: sourceId, value: value, valueType: vtAny, line: cr.data.keyLine})
 		}
 	}
+
+	return nil
 }
 
 func (cr *configReader) addJsonValue(structInfo structInfo, it intermediateTree, name, value, key string, vType valueType, sourceId int) error {
-	if structInfo.isSlice {
-		if vType == vtString && value == nilDefault {
+	if structInfo.isRemain {
+		name = strings.TrimSuffix(name, ".")
+		if name != "" && key != "" {
+			name += "."
+		}
+		cr.addRemainValue(structInfo, it, name+key, value, vType, structInfo.isSlice, sourceId)
+	} else if structInfo.isRaw {
+		if vType == vtString {
+			value = quoteJsonString(value)
+		} else if vType == vtNull {
+			value = "null"
+		}
+		cr.addRawValue(structInfo, it, name, value, "", false, sourceId)
+	} else if structInfo.isSlice {
+		if vType == vtString && (value == nilDefault || isUnsetValue(value)) {
+			// markers don't define the type of the slice items
 			vType = vtNull
 		}
-		if _, ok := it[name]; !ok {
-			it[name] = []intermediateData{{source: sourceId, value: []string{}, valueType: vType}}
-		} else if !slices.ContainsFunc(it[name], func(data intermediateData) bool { return data.source == sourceId }) {
-			it[name] = append(it[name], intermediateData{source: sourceId, value: []string{}, valueType: vType})
+		if key != "" {
+			return cr.addIndexValue(structInfo, it, name, value, key, vType, sourceId)
+		}
+		if !slices.ContainsFunc(it[name], func(data intermediateData) bool { return isSourceSlice(data, sourceId) }) {
+			it[name] = append(it[name], intermediateData{source: sourceId, value: []string{}, valueType: vType, line: cr.data.keyLine})
 		}
 		for i, v := range it[name] {
+			if !isSourceSlice(v, sourceId) {
+				continue
+			}
 			if v.valueType == vtNull && vType != vtNull {
 				it[name][i].valueType = vType
 				v.valueType = vType
@@ -88,27 +156,35 @@ func (cr *configReader) addJsonValue(structInfo structInfo, it intermediateTree,
 			if v.valueType != vType && vType != vtNull {
 				return errors.New("different value types in slice \"" + name + "\" " + cr.currentPointInfo())
 			}
-			if v.source == sourceId {
-				it[name][i].value = append(it[name][i].value.([]string), value)
-				break
-			}
+			it[name][i].value = append(it[name][i].value.([]string), value)
+			break
 		}
 	} else if structInfo.isMap {
+		if key == "" && vType == vtString && value == unsetValue {
+			vType = vtUnset
+		} else if key == "" && vType != vtNull {
+			return errors.New("invalid map value for key \"" + name + "\" " + cr.currentPointInfo())
+		}
 		if _, ok := it[name]; !ok {
-			it[name] = []intermediateData{{source: sourceId, value: map[string]string{}, valueType: vType}}
+			it[name] = []intermediateData{{source: sourceId, value: map[string]string{}, valueType: vType, line: cr.data.keyLine}}
 		} else if !slices.ContainsFunc(it[name], func(data intermediateData) bool { return data.source == sourceId }) {
-			it[name] = append(it[name], intermediateData{source: sourceId, value: map[string]string{}, valueType: vType})
+			it[name] = append(it[name], intermediateData{source: sourceId, value: map[string]string{}, valueType: vType, line: cr.data.keyLine})
 		}
 		for i, v := range it[name] {
-			if v.source == sourceId {
+			if v.source != sourceId {
+				continue
+			}
+			if key != "" {
 				it[name][i].value.(map[string]string)[key] = value
+			} else if vType == vtUnset || vType == vtNull {
+				it[name][i].valueType = vType
 			}
 		}
 	} else {
 		if _, ok := it[name]; !ok {
-			it[name] = []intermediateData{{source: sourceId, value: value, valueType: vType}}
+			it[name] = []intermediateData{{source: sourceId, value: value, valueType: vType, line: cr.data.keyLine}}
 		} else {
-			it[name] = append(it[name], intermediateData{source: sourceId, value: value, valueType: vType})
+			it[name] = append(it[name], intermediateData{source: sourceId, value: value, valueType: vType, line: cr.data.keyLine})
 		}
 	}
 
@@ -163,6 +239,14 @@ func (cr *configReader) processEofError(err error) error {
 	return err
 }
 
+// Get the key name in the case used for matching, lowercased unless CaseSensitive is set
+func (cr *configReader) keyCase(name string) string {
+	if cr.options.CaseSensitive {
+		return name
+	}
+	return strings.ToLower(name)
+}
+
 func (cr *configReader) processNamedError(err error, source string) error {
 	return errors.New("error in " + source + " \"" + cr.data.currentFile + "\": " + err.Error())
 }
@@ -0,0 +1,123 @@
+package configuration
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Values of the slice items set by index in one source, e.g. "hosts[2] = x"
+type indexItems map[int]string
+
+// Split the key of the slice item set by index: "hosts[2]" or json pointer "/hosts/2"
+func splitIndexName(name string) (string, string, bool) {
+	if path, ok := strings.CutPrefix(name, "/"); ok {
+		segments := strings.Split(path, "/")
+		if len(segments) < 2 {
+			return "", "", false
+		}
+		key := segments[len(segments)-1]
+		if _, ok := parseIndex(key); !ok {
+			return "", "", false
+		}
+		for i, s := range segments {
+			segments[i] = strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+		}
+		return strings.Join(segments[:len(segments)-1], "."), key, true
+	}
+
+	base, key, isSlice := splitCollectionName(name)
+	if _, ok := parseIndex(key); isSlice || !ok {
+		return "", "", false
+	}
+	return base, key, true
+}
+
+func parseIndex(key string) (int, bool) {
+	if key == "" || strings.Trim(key, "0123456789") != "" {
+		return 0, false
+	}
+	index, err := strconv.Atoi(key)
+	return index, err == nil
+}
+
+func isSourceSlice(data intermediateData, sourceId int) bool {
+	_, ok := data.value.([]string)
+	return data.source == sourceId && ok
+}
+
+// Add the value of the slice item set by index
+// key - index of the item
+func (cr *configReader) addIndexValue(info structInfo, it intermediateTree, name, value, key string, vType valueType, sourceId int) error {
+	index, ok := parseIndex(key)
+	if !ok {
+		return errors.New("invalid index \"" + key + "\" of key \"" + name + "\" " + cr.currentPointInfo())
+	} else if info.size > 0 && index >= info.size {
+		return errors.New("index " + key + " is out of range of key \"" + name + "\" with size " + strconv.Itoa(info.size) + " " + cr.currentPointInfo())
+	} else if isUnsetValue(value) {
+		// the items of a slice can't be removed by index, indexes of the next items would change
+		return errors.New("slice item \"" + name + "[" + key + "]\" can't be unset by index, use \"" + name + "[] = " + unsetItemPrefix + "value\" " + cr.currentPointInfo())
+	}
+
+	for i, d := range it[name] {
+		if items, ok := d.value.(indexItems); ok && d.source == sourceId {
+			items[index] = value
+			if d.valueType == vtNull || d.valueType == vtAny {
+				it[name][i].valueType = vType
+			}
+			return nil
+		}
+	}
+	it[name] = append(it[name], intermediateData{source: sourceId, value: indexItems{index: value}, valueType: vType, line: cr.data.keyLine})
+	return nil
+}
+
+// Replace the items of the merged slice, the slice is extended with zero values if needed
+func setIndexItems(info structInfo, values []string, items indexItems) []string {
+	result := slices.Clone(values)
+	indexes := make([]int, 0, len(items))
+	for index := range items {
+		indexes = append(indexes, index)
+	}
+	slices.Sort(indexes)
+
+	for _, index := range indexes {
+		for len(result) <= index {
+			result = append(result, zeroItemValue(info))
+		}
+		result[index] = items[index]
+	}
+	return result
+}
+
+func zeroItemValue(info structInfo) string {
+	if info.isPointer {
+		return nilDefault
+	} else if info.useParser {
+		return ""
+	}
+	return formatScalar(reflect.Zero(info.fieldType))
+}
+
+// Read "name_N" environment variables of the slice field, e.g. HOSTS_2, the name is case insensitive
+// name - key name of the field or its alias, variable - name of its environment variable
+func (cr *configReader) readEnvironmentIndexes(it intermediateTree, info structInfo, name, variable string, sourceId int) error {
+	prefix := variable + "_"
+	for _, env := range os.Environ() {
+		envName, value, _ := strings.Cut(env, "=")
+		if len(envName) <= len(prefix) || cr.keyCase(envName[:len(prefix)]) != cr.keyCase(prefix) {
+			continue
+		}
+		key := envName[len(prefix):]
+		if _, ok := parseIndex(key); !ok {
+			continue
+		}
+		if err := cr.addValue(info, it, name, value, key, false, sourceId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReadConfig_index_success(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Hosts  []string `env:"hosts"`
+		Ports  []int    `env:"ports"`
+		Ids    [3]int   `env:"ids"`
+		Ptrs   []*int   `env:"ptrs"`
+		Append []string `env:"append" merge:"append"`
+		Tags   []string `env:"tags"`
+		Db     struct {
+			Names []string `env:"names"`
+		} `env:"db"`
+	}{}
+	t.Setenv("TAGS_1", "env")
+	base := "hosts = a,b,c\nports = 1,2\nids = 1,2\nappend = a\ntags = x,y\n[db]\nnames = n1,n2"
+	site := "hosts[2] = x\nports[3] = 4\nids[2] = 3\nptrs[1] = 5\nappend[1] = b\n"
+	host := `{"hosts[0]": "y", "/ports/0": 0, "db": {"names[1]": "n"}, "append": ["c"]}`
+
+	// Act
+	err := NewConfigReader().
+		AddString(base, FtIni, "base").
+		AddString(site, FtEnv, "site").
+		AddString(host, FtJson, "host").
+		AddEnvironment().
+		ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, []string{"y", "b", "x"}, config.Hosts)
+	require.Equal(t, []int{0, 2, 0, 4}, config.Ports)
+	require.Equal(t, [3]int{1, 2, 3}, config.Ids)
+	require.Nil(t, config.Ptrs[0])
+	require.Equal(t, 5, *config.Ptrs[1])
+	require.Equal(t, []string{"a", "b", "c"}, config.Append)
+	require.Equal(t, []string{"x", "env"}, config.Tags)
+	require.Equal(t, []string{"n1", "n"}, config.Db.Names)
+}
+
+type testCaseIndexError struct {
+	data string
+	ft   formatType
+	err  string
+}
+
+func Test_ReadConfig_index_error_cases(t *testing.T) {
+	// Arrange
+	cases := []testCaseIndexError{
+		{"ids[3] = 1", FtEnv, "index 3 is out of range of key \"ids\" with size 3"},
+		{"hosts[a] = 1", FtEnv, "invalid index \"a\" of key \"hosts\""},
+		{"hosts[-1] = 1", FtIni, "invalid index \"-1\" of key \"hosts\""},
+		{`{"ids[5]": 1}`, FtJson, "index 5 is out of range of key \"ids\" with size 3"},
+		{`{"hosts[1]": [1]}`, FtJson, "slice item \"hosts[1]\" must be a single value"},
+		{"hosts = a,b,c\nhosts[1] = *unset", FtEnv, "slice item \"hosts[1]\" can't be unset by index"},
+		{"hosts[0] = *unset:a", FtIni, "slice item \"hosts[0]\" can't be unset by index"},
+		{`{"/hosts/1": "*unset"}`, FtJson, "slice item \"hosts[1]\" can't be unset by index"},
+	}
+
+	// Act & Assert
+	for _, c := range cases {
+		t.Log("Test case:", c.data)
+		test_ReadConfig_index_error_cases(t, c)
+	}
+}
+
+func test_ReadConfig_index_error_cases(t *testing.T, testCase testCaseIndexError) {
+	// Arrange
+	config := &struct {
+		Hosts []string `env:"hosts"`
+		Ids   [3]int   `env:"ids"`
+	}{}
+
+	// Act
+	err := NewConfigReader().AddString(testCase.data, testCase.ft, "test").ReadConfig(config)
+
+	// Assert
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), testCase.err)
+}
+
+func Test_ReadConfig_environment_slice(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Hosts  []string          `env:"hosts"`
+		Limits map[string]string `env:"limits"`
+	}{}
+	t.Setenv("hosts", "a,b")
+	t.Setenv("limits", "a:1")
+
+	// Act
+	err := NewConfigReader().AddEnvironment().ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, []string{"a", "b"}, config.Hosts)
+	require.Equal(t, map[string]string{"a": "1"}, config.Limits)
+}
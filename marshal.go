@@ -0,0 +1,474 @@
+package configuration
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+type marshalItem struct {
+	key        string
+	value      interface{} // string, []string or map[string]string
+	valueType  valueType   // type of the value or collection items
+	separator  string
+	separator2 string
+	isMap      bool
+	isNil      bool
+	isNullable bool // value or collection items can be "*nil"
+	comments   []string
+	isSample   bool // show alternative forms of collections
+}
+
+// Write the user config struct in env, ini or json format
+// secret fields are redacted
+// userConfig - pointer to the user config struct
+// formatType - format to write
+func Marshal(userConfig interface{}, formatType formatType) ([]byte, error) {
+	cr := &configReader{}
+	si, err := cr.getStructInfo(userConfig, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalItems(cr.getMarshalItems(si), formatType)
+}
+
+func (cr *configReader) getMarshalItems(si []structInfo) []marshalItem {
+	items := make([]marshalItem, 0, len(si))
+	for _, info := range si {
+		if info.isRemain {
+			items = append(items, cr.getRemainMarshalItems(info)...)
+			continue
+		} else if info.isRaw {
+			items = append(items, cr.getRawMarshalItems(info)...)
+			continue
+		}
+		item := marshalItem{
+			key:        info.keyName,
+			valueType:  getFieldValueType(info),
+			separator:  info.separator,
+			separator2: info.separator2,
+			isMap:      info.isMap,
+			isNullable: info.isPointer,
+		}
+
+		if info.isSlice {
+			if info.field.Kind() == reflect.Slice && info.field.IsNil() {
+				item.isNil = true
+			} else {
+				values := formatSlice(info.field)
+				for i, v := range values {
+					if v != nilDefault || !info.isPointer {
+						values[i] = cr.redactIfSecret(info, v)
+					}
+				}
+				item.value = values
+			}
+		} else if info.isMap {
+			if info.field.IsNil() {
+				item.isNil = true
+			} else {
+				values := formatMap(info.field)
+				for k, v := range values {
+					values[k] = cr.redactIfSecret(info, v)
+				}
+				item.value = values
+			}
+		} else if info.isPointer && info.field.IsNil() {
+			item.isNil = true
+		} else {
+			item.value = cr.redactIfSecret(info, formatScalar(info.field))
+		}
+		if info.isSecret {
+			// redacted values are not numbers or bools anymore
+			item.valueType = vtString
+		}
+
+		items = append(items, item)
+	}
+	return items
+}
+
+// Get an item for every key of the field with "remain" option
+func (cr *configReader) getRemainMarshalItems(info structInfo) []marshalItem {
+	prefix := strings.TrimSuffix(info.keyName, remainKey)
+	values := map[string]reflect.Value{}
+	iter := info.field.MapRange()
+	for iter.Next() {
+		values[iter.Key().String()] = iter.Value()
+	}
+
+	items := make([]marshalItem, 0, len(values))
+	for _, k := range sortedMapKeys(values) {
+		v := values[k]
+		if v.Kind() == reflect.Interface && !v.IsNil() {
+			v = v.Elem()
+		}
+		item := marshalItem{
+			key:        prefix + k,
+			valueType:  getKindValueType(v),
+			separator:  info.separator,
+			separator2: info.separator2,
+			isNullable: true,
+		}
+		if v.Kind() == reflect.Interface {
+			item.isNil = true
+		} else if v.Kind() == reflect.Slice {
+			values := formatSlice(v)
+			for i, s := range values {
+				values[i] = cr.redactIfSecret(info, s)
+			}
+			item.value = values
+			item.valueType = vtString
+			if v.Len() > 0 {
+				item.valueType = getKindValueType(v.Index(0).Elem())
+			}
+		} else {
+			item.value = cr.redactIfSecret(info, formatScalar(v))
+		}
+		if info.isSecret {
+			item.valueType = vtString
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func getKindValueType(v reflect.Value) valueType {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return vtNumber
+	case reflect.Bool:
+		return vtBool
+	}
+	return vtString
+}
+
+func getFieldValueType(info structInfo) valueType {
+	if info.useParser {
+		return vtString
+	}
+	switch info.fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return vtNumber
+	case reflect.Int64:
+		if info.fieldType.String() == "time.Duration" {
+			return vtString
+		}
+		return vtNumber
+	case reflect.Bool:
+		return vtBool
+	}
+	return vtString
+}
+
+func marshalItems(items []marshalItem, formatType formatType) ([]byte, error) {
+	var buffer bytes.Buffer
+	var err error = nil
+	switch formatType {
+	case FtEnv:
+		err = writeEnv(&buffer, items)
+	case FtIni:
+		err = writeIni(&buffer, items)
+	case FtJson:
+		err = writeJson(&buffer, items)
+	default:
+		err = errors.New("unsupported format type")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func writeEnv(buffer *bytes.Buffer, items []marshalItem) error {
+	for _, item := range items {
+		err := writeKeyValue(buffer, item.key, item, quoteEnvValue, envComment)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeIni(buffer *bytes.Buffer, items []marshalItem) error {
+	sections := []string{}
+	sectionItems := map[string][]marshalItem{}
+	for _, item := range items {
+		section := ""
+		if index := strings.LastIndex(item.key, "."); index > 0 {
+			section = item.key[:index]
+		}
+		if _, ok := sectionItems[section]; !ok {
+			sections = append(sections, section)
+		}
+		sectionItems[section] = append(sectionItems[section], item)
+	}
+
+	if _, ok := sectionItems[""]; ok {
+		for _, item := range sectionItems[""] {
+			if err := writeKeyValue(buffer, item.key, item, quoteIniValue, iniComment); err != nil {
+				return err
+			}
+		}
+	}
+	for _, section := range sections {
+		if section == "" {
+			continue
+		}
+		if buffer.Len() > 0 {
+			buffer.WriteString("\n")
+		}
+		buffer.WriteString("[" + section + "]\n")
+		for _, item := range sectionItems[section] {
+			if err := writeKeyValue(buffer, item.key[len(section)+1:], item, quoteIniValue, iniComment); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeKeyValue(buffer *bytes.Buffer, key string, item marshalItem, quote func(string) (string, error), comment string) error {
+	for _, c := range item.comments {
+		buffer.WriteString(comment + " " + c + "\n")
+	}
+
+	err := writeValue(buffer, key, item, quote)
+	if err != nil {
+		return err
+	}
+
+	if item.isSample {
+		if item.isMap {
+			buffer.WriteString(comment + " " + key + "[key] = value\n")
+		} else if _, ok := item.value.([]string); ok {
+			buffer.WriteString(comment + " " + key + "[] = value\n")
+		}
+	}
+	return nil
+}
+
+func writeValue(buffer *bytes.Buffer, key string, item marshalItem, quote func(string) (string, error)) error {
+	if item.isNil {
+		if item.isMap {
+			return nil
+		}
+		buffer.WriteString(key + " = " + nilDefault + "\n")
+		return nil
+	}
+
+	switch value := item.value.(type) {
+	case string:
+		quoted, err := quote(value)
+		if err != nil {
+			return errors.New("can't write value of key " + item.key + ": " + err.Error())
+		}
+		if quoted == "" {
+			buffer.WriteString(key + " =\n")
+			return nil
+		}
+		buffer.WriteString(key + " = " + quoted + "\n")
+	case []string:
+		if len(value) == 0 {
+			buffer.WriteString(key + " =\n")
+			return nil
+		}
+		joinable := true
+		for _, v := range value {
+			if quoted, err := quote(v); err != nil || quoted != v || v == "" || strings.Contains(v, item.separator) {
+				joinable = false
+				break
+			}
+		}
+		joined := strings.Join(value, item.separator)
+		if quoted, err := quote(joined); joinable && err == nil && quoted == joined {
+			buffer.WriteString(key + " = " + joined + "\n")
+			return nil
+		}
+		for _, v := range value {
+			quoted, err := quote(v)
+			if err != nil {
+				return errors.New("can't write value of key " + item.key + ": " + err.Error())
+			}
+			buffer.WriteString(key + "[] = " + quoted + "\n")
+		}
+	case map[string]string:
+		keys := sortedMapKeys(value)
+		joinable := true
+		pairs := make([]string, 0, len(keys))
+		for _, k := range keys {
+			v := value[k]
+			if quoted, err := quote(v); err != nil || quoted != v || k == "" ||
+				strings.Contains(k+v, item.separator) || strings.Contains(k, item.separator2) || strings.ContainsAny(k, " \t") {
+				joinable = false
+				break
+			}
+			pairs = append(pairs, k+item.separator2+v)
+		}
+		joined := strings.Join(pairs, item.separator)
+		if quoted, err := quote(joined); joinable && len(pairs) > 0 && err == nil && quoted == joined {
+			buffer.WriteString(key + " = " + joined + "\n")
+			return nil
+		}
+		for _, k := range keys {
+			quoted, err := quote(value[k])
+			if err != nil {
+				return errors.New("can't write value of key " + item.key + ": " + err.Error())
+			}
+			buffer.WriteString(key + "[" + k + "] = " + quoted + "\n")
+		}
+	}
+	return nil
+}
+
+func quoteEnvValue(value string) (string, error) {
+	return quoteValue(value, " \t#\"'\\", false)
+}
+
+func quoteIniValue(value string) (string, error) {
+	return quoteValue(value, "#;\"'\\", true)
+}
+
+func quoteValue(value, special string, trimmed bool) (string, error) {
+	needsQuote := strings.ContainsAny(value, special) ||
+		trimmed && strings.Trim(value, " \t") != value
+	if strings.ContainsAny(value, "\r\n") {
+		if needsQuote || strings.Contains(value, "\r") || strings.HasPrefix(value, "\n") ||
+			strings.HasSuffix(value, "\n") || strings.Contains(value, "\n\n") {
+			return "", errors.New("multiline value can't be quoted")
+		}
+		return strings.ReplaceAll(value, "\n", "\\\n"), nil
+	}
+	if !needsQuote {
+		return value, nil
+	}
+
+	if strings.Contains(value, "\"") && !strings.Contains(value, "'") {
+		return "'" + value + "'", nil
+	}
+	return "\"" + strings.ReplaceAll(value, "\"", "\\\"") + "\"", nil
+}
+
+type jsonNode struct {
+	keys     []string
+	children map[string]*jsonNode
+	items    map[string]marshalItem
+}
+
+func newJsonNode() *jsonNode {
+	return &jsonNode{children: map[string]*jsonNode{}, items: map[string]marshalItem{}}
+}
+
+func writeJson(buffer *bytes.Buffer, items []marshalItem) error {
+	root := newJsonNode()
+	for _, item := range items {
+		node := root
+		path := strings.Split(item.key, ".")
+		for _, name := range path[:len(path)-1] {
+			child, ok := node.children[name]
+			if !ok {
+				if _, ok := node.items[name]; ok {
+					return errors.New("key " + item.key + " conflicts with value " + name)
+				}
+				child = newJsonNode()
+				node.children[name] = child
+				node.keys = append(node.keys, name)
+			}
+			node = child
+		}
+		name := path[len(path)-1]
+		if _, ok := node.children[name]; ok {
+			return errors.New("value " + item.key + " conflicts with section")
+		}
+		if _, ok := node.items[name]; !ok {
+			node.keys = append(node.keys, name)
+		}
+		node.items[name] = item
+	}
+
+	root.write(buffer, "")
+	buffer.WriteString("\n")
+	return nil
+}
+
+func (node *jsonNode) write(buffer *bytes.Buffer, indent string) {
+	buffer.WriteString("{")
+	for i, name := range node.keys {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+		if item, ok := node.items[name]; ok {
+			for _, c := range item.comments {
+				buffer.WriteString("\n" + indent + "  " + jsonComment + " " + c)
+			}
+		}
+		buffer.WriteString("\n" + indent + "  " + quoteJsonString(name) + ": ")
+		if child, ok := node.children[name]; ok {
+			child.write(buffer, indent+"  ")
+		} else {
+			node.items[name].writeJson(buffer)
+		}
+	}
+	if len(node.keys) > 0 {
+		buffer.WriteString("\n" + indent)
+	}
+	buffer.WriteString("}")
+}
+
+func (item marshalItem) writeJson(buffer *bytes.Buffer) {
+	if item.isNil {
+		buffer.WriteString("null")
+		return
+	}
+
+	switch value := item.value.(type) {
+	case string:
+		buffer.WriteString(item.jsonValue(value))
+	case []string:
+		buffer.WriteString("[")
+		for i, v := range value {
+			if i > 0 {
+				buffer.WriteString(", ")
+			}
+			buffer.WriteString(item.jsonValue(v))
+		}
+		buffer.WriteString("]")
+	case map[string]string:
+		buffer.WriteString("{")
+		for i, k := range sortedMapKeys(value) {
+			if i > 0 {
+				buffer.WriteString(", ")
+			}
+			buffer.WriteString(quoteJsonString(k) + ": " + item.jsonValue(value[k]))
+		}
+		buffer.WriteString("}")
+	}
+}
+
+func (item marshalItem) jsonValue(value string) string {
+	if item.isNullable && value == nilDefault {
+		return "null"
+	} else if item.valueType == vtNumber || item.valueType == vtBool || item.valueType == vtNull {
+		if value == "" {
+			return "null"
+		}
+		return value
+	}
+	return quoteJsonString(value)
+}
+
+// Quote the string by RFC 8259, html characters are not escaped
+func quoteJsonString(value string) string {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
@@ -0,0 +1,203 @@
+package configuration
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCaseMarshalSub struct {
+	Name    string        `env:"name"`
+	Timeout time.Duration `env:"timeout"`
+	Pool    struct {
+		Size uint16 `env:"size"`
+	} `env:"pool"`
+}
+
+type testCaseMarshalConfig struct {
+	Int      int                `env:"int"`
+	Float    float64            `env:"float"`
+	Bool     bool               `env:"bool"`
+	String   string             `env:"string"`
+	Quoted   string             `env:"quoted"`
+	Time     time.Time          `env:"time"`
+	Pointer  *int               `env:"pointer"`
+	NilPtr   *string            `env:"nil_ptr"`
+	Slice    []string           `env:"slice" sep:";"`
+	Spaces   []string           `env:"spaces"`
+	NilSlice []int              `env:"nil_slice"`
+	PtrSlice []*int             `env:"ptr_slice"`
+	Array    [3]float32         `env:"array"`
+	Map      map[string]int     `env:"map" sep2:"="`
+	StrMap   map[string]string  `env:"str_map"`
+	NilMap   map[string]float64 `env:"nil_map"`
+	Sub      testCaseMarshalSub `env:"sub"`
+}
+
+func newTestCaseMarshalConfig() *testCaseMarshalConfig {
+	config := &testCaseMarshalConfig{
+		Int:      -1,
+		Float:    1.5,
+		Bool:     true,
+		String:   "value",
+		Quoted:   "it's a \"value\" # not a comment",
+		Time:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Pointer:  addr(10),
+		Slice:    []string{"a", "b,c"},
+		Spaces:   []string{"a b", "c"},
+		PtrSlice: []*int{addr(1), nil, addr(3)},
+		Array:    [3]float32{1.1, 2.2, 0},
+		Map:      map[string]int{"a": 1, "b": 2},
+		StrMap:   map[string]string{"k": "v v"},
+	}
+	config.Sub.Name = "sub"
+	config.Sub.Timeout = time.Minute
+	config.Sub.Pool.Size = 5
+	return config
+}
+
+func Test_Marshal_roundTrip_cases(t *testing.T) {
+	// Arrange
+	cases := []formatType{FtEnv, FtIni, FtJson}
+
+	// Act & Assert
+	for _, c := range cases {
+		t.Log("Test case:", c)
+		test_Marshal_roundTrip(t, c)
+	}
+}
+
+func test_Marshal_roundTrip(t *testing.T, ft formatType) {
+	// Arrange
+	config := newTestCaseMarshalConfig()
+	result := &testCaseMarshalConfig{}
+
+	// Act
+	data, err := Marshal(config, ft)
+	require.Nil(t, err)
+	err = NewConfigReader().AddString(string(data), ft, "marshal").ReadConfig(result)
+
+	// Assert
+	require.Nil(t, err, string(data))
+	require.Equal(t, config, result, string(data))
+}
+
+func Test_Marshal_env(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Host   string         `env:"host"`
+		Hosts  []string       `env:"hosts"`
+		Limits map[string]int `env:"limits"`
+		Db     struct {
+			Port *int `env:"port"`
+		} `env:"db"`
+	}{Host: "localhost", Hosts: []string{"a", "b"}, Limits: map[string]int{"x": 1}}
+
+	// Act
+	data, err := Marshal(config, FtEnv)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "host = localhost\nhosts = a,b\nlimits = x:1\ndb.port = *nil\n", string(data))
+}
+
+func Test_Marshal_ini(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Host string `env:"host"`
+		Db   struct {
+			Port int `env:"port"`
+			Pool struct {
+				Size int `env:"size"`
+			} `env:"pool"`
+		} `env:"db"`
+	}{Host: "my host"}
+
+	// Act
+	data, err := Marshal(config, FtIni)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "host = my host\n\n[db]\nport = 0\n\n[db.pool]\nsize = 0\n", string(data))
+}
+
+func Test_Marshal_json(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Host     string   `env:"host"`
+		Password string   `env:"password,secret"`
+		Ports    []int    `env:"ports"`
+		Ptrs     []*int   `env:"ptrs"`
+		Flag     *bool    `env:"flag"`
+		Names    []string `env:"names"`
+	}{Host: "h", Password: "p", Ports: []int{1, 2}, Ptrs: []*int{nil}}
+
+	// Act
+	data, err := Marshal(config, FtJson)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "{\n  \"host\": \"h\",\n  \"password\": \"******\",\n  \"ports\": [1, 2],\n"+
+		"  \"ptrs\": [null],\n  \"flag\": null,\n  \"names\": null\n}\n", string(data))
+}
+
+func Test_Marshal_json_secret_number(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Pin    int            `env:"pin,secret"`
+		Admin  bool           `env:"admin,secret"`
+		Codes  []int          `env:"codes,secret"`
+		Limits map[string]int `env:"limits,secret"`
+	}{Pin: 1234, Admin: true, Codes: []int{1, 2}, Limits: map[string]int{"a": 1}}
+
+	// Act
+	data, err := Marshal(config, FtJson)
+
+	// Assert
+	require.Nil(t, err)
+	require.True(t, json.Valid(data))
+	require.Equal(t, "{\n  \"pin\": \"******\",\n  \"admin\": \"******\",\n  \"codes\": [\"******\", \"******\"],\n"+
+		"  \"limits\": {\"a\": \"******\"}\n}\n", string(data))
+}
+
+func Test_Marshal_json_escape(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Path  string            `env:"path"`
+		Text  string            `env:"text"`
+		Items []string          `env:"items"`
+		Tags  map[string]string `env:"tags"`
+	}{Path: `C:\dir`, Text: "a\nb\t\"c\" <d>\x01", Items: []string{"\n"}, Tags: map[string]string{"k\"": "v\\"}}
+
+	// Act
+	b, err := Marshal(config, FtJson)
+
+	// Assert
+	require.Nil(t, err)
+	require.Contains(t, string(b), `"text": "a\nb\t\"c\" <d>\u0001"`)
+	result := map[string]interface{}{}
+	require.Nil(t, json.Unmarshal(b, &result))
+	require.Equal(t, config.Path, result["path"])
+	require.Equal(t, config.Text, result["text"])
+	require.Equal(t, []interface{}{"\n"}, result["items"])
+	require.Equal(t, map[string]interface{}{"k\"": "v\\"}, result["tags"])
+}
+
+func Test_Marshal_error_cases(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Text string `env:"text"`
+	}{Text: "multi line\nvalue"}
+
+	// Act
+	_, errEnv := Marshal(config, FtEnv)
+	_, errFormat := Marshal(config, ftEnvironment)
+	_, errConfig := Marshal(1, FtEnv)
+
+	// Assert
+	require.NotNil(t, errEnv)
+	require.NotNil(t, errFormat)
+	require.NotNil(t, errConfig)
+}
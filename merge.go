@@ -0,0 +1,122 @@
+package configuration
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+const (
+	mergeReplace   = "replace"
+	mergeAppend    = "append"
+	mergePrepend   = "prepend"
+	mergeUnion     = "union"
+	mergeFirst     = "first"
+	mergeDeep      = "deep"
+	mergeShallow   = "shallow"
+	mergeKeepFirst = "keep-first"
+)
+
+var (
+	sliceMergeStrategies = []string{mergeReplace, mergeAppend, mergePrepend, mergeUnion, mergeFirst}
+	mapMergeStrategies   = []string{mergeReplace, mergeShallow, mergeDeep, mergeKeepFirst, mergeFirst}
+)
+
+// Get the merge strategy of the collection field from the "merge" tag
+// without the tag "append" option gives "append" for slices and "deep" for maps, otherwise "replace"
+func getMergeStrategy(merge string, appendOption, isSlice, isMap bool, fieldName string) (string, error) {
+	merge = strings.ToLower(strings.TrimSpace(merge))
+	if merge == "" {
+		if appendOption && isMap {
+			return mergeDeep, nil
+		} else if appendOption && isSlice {
+			return mergeAppend, nil
+		}
+		return mergeReplace, nil
+	}
+
+	if isSlice && slices.Contains(sliceMergeStrategies, merge) || isMap && slices.Contains(mapMergeStrategies, merge) {
+		return merge, nil
+	} else if !isSlice && !isMap {
+		return "", errors.New("merge tag is for collections only, field " + fieldName)
+	}
+	return "", errors.New("unsupported merge strategy \"" + merge + "\" for field " + fieldName)
+}
+
+// Whether the collection field takes the values from all the sources
+func (info structInfo) mergesAll() bool {
+	switch info.merge {
+	case mergeAppend, mergePrepend, mergeUnion, mergeDeep, mergeKeepFirst:
+		return true
+	}
+	return false
+}
+
+// Merge the slice values of all the sources by the merge strategy of the field
+// items set by index replace the items of the slice merged from the earlier sources
+func mergeSliceData(info structInfo, data []intermediateData) ([]string, valueType) {
+	var result []string
+	var vType valueType = vtAny
+	isSet := false
+	for _, d := range data {
+		if items, ok := d.value.(indexItems); ok {
+			result = setIndexItems(info, result, items)
+			if !isSet {
+				vType = d.valueType
+			}
+			continue
+		}
+
+		values := d.value.([]string)
+		if info.merge == mergeFirst && isSet {
+			continue
+		} else if info.merge == mergePrepend {
+			result = append(slices.Clone(values), result...)
+		} else if info.mergesAll() {
+			result = append(result, values...)
+		} else {
+			result = values
+		}
+		isSet = true
+		vType = d.valueType
+	}
+
+	if info.merge == mergeUnion {
+		unique := make([]string, 0, len(result))
+		for _, v := range result {
+			if !slices.Contains(unique, v) {
+				unique = append(unique, v)
+			}
+		}
+		result = unique
+	}
+	if result == nil {
+		result = []string{}
+	}
+	return result, vType
+}
+
+// Merge the map values of all the sources by the merge strategy of the field
+func mergeMapData(info structInfo, data []intermediateData) (map[string]string, valueType) {
+	if len(data) == 0 {
+		return map[string]string{}, vtAny
+	} else if info.merge == mergeFirst {
+		return data[0].value.(map[string]string), data[0].valueType
+	} else if !info.mergesAll() {
+		last := data[len(data)-1]
+		return last.value.(map[string]string), last.valueType
+	}
+
+	result := map[string]string{}
+	var vType valueType = vtAny
+	for _, d := range data {
+		for k, v := range d.value.(map[string]string) {
+			if _, ok := result[k]; ok && info.merge == mergeKeepFirst {
+				continue
+			}
+			result[k] = v
+			vType = d.valueType
+		}
+	}
+	return result, vType
+}
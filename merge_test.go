@@ -0,0 +1,90 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReadConfig_merge_success(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Replace   []string          `env:"replace" merge:"replace"`
+		Append    []string          `env:"append" merge:"append"`
+		Prepend   []string          `env:"prepend" merge:"prepend"`
+		Union     []string          `env:"union" merge:"union"`
+		First     []string          `env:"first" merge:"first"`
+		Deep      map[string]string `env:"deep" merge:"deep"`
+		Shallow   map[string]string `env:"shallow" merge:"shallow"`
+		KeepFirst map[string]string `env:"keep_first" merge:"keep-first"`
+		FirstMap  map[string]string `env:"first_map" merge:"first"`
+		Option    []string          `env:"option,append"`
+	}{}
+	base := "replace = a,b\nappend = a,b\nprepend = a,b\nunion = a,b\nfirst = a,b\n" +
+		"deep = a:1,b:1\nshallow = a:1,b:1\nkeep_first = a:1,b:1\nfirst_map = a:1,b:1\noption = a"
+	site := `{"replace": ["b", "c"], "append": ["b", "c"], "prepend": ["b", "c"], "union": ["b", "c"], "first": ["b", "c"],
+		"deep": {"b": "2", "c": "2"}, "shallow": {"b": "2", "c": "2"}, "keep_first": {"b": "2", "c": "2"},
+		"first_map": {"b": "2", "c": "2"}, "option": ["b"]}`
+
+	// Act
+	err := NewConfigReader().AddString(base, FtEnv, "base").AddString(site, FtJson, "site").ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, []string{"b", "c"}, config.Replace)
+	require.Equal(t, []string{"a", "b", "b", "c"}, config.Append)
+	require.Equal(t, []string{"b", "c", "a", "b"}, config.Prepend)
+	require.Equal(t, []string{"a", "b", "c"}, config.Union)
+	require.Equal(t, []string{"a", "b"}, config.First)
+	require.Equal(t, map[string]string{"a": "1", "b": "2", "c": "2"}, config.Deep)
+	require.Equal(t, map[string]string{"b": "2", "c": "2"}, config.Shallow)
+	require.Equal(t, map[string]string{"a": "1", "b": "1", "c": "2"}, config.KeepFirst)
+	require.Equal(t, map[string]string{"a": "1", "b": "1"}, config.FirstMap)
+	require.Equal(t, []string{"a", "b"}, config.Option)
+}
+
+type testCaseGetMergeStrategy struct {
+	merge        string
+	appendOption bool
+	isSlice      bool
+	isMap        bool
+	expected     string
+	err          string
+}
+
+func Test_getMergeStrategy_cases(t *testing.T) {
+	// Arrange
+	cases := []testCaseGetMergeStrategy{
+		{"", false, true, false, mergeReplace, ""},
+		{"", true, true, false, mergeAppend, ""},
+		{"", true, false, true, mergeDeep, ""},
+		{"", true, false, false, mergeReplace, ""},
+		{"Union", false, true, false, mergeUnion, ""},
+		{"keep-first", false, false, true, mergeKeepFirst, ""},
+		{"first", true, false, true, mergeFirst, ""},
+		{"deep", false, true, false, "", "unsupported merge strategy \"deep\" for field F"},
+		{"shallow", false, false, true, mergeShallow, ""},
+		{"union", false, false, true, "", "unsupported merge strategy \"union\" for field F"},
+		{"last", false, true, false, "", "unsupported merge strategy \"last\" for field F"},
+		{"append", false, false, false, "", "merge tag is for collections only, field F"},
+	}
+
+	// Act & Assert
+	for _, c := range cases {
+		t.Log("Test case:", c.merge)
+		test_getMergeStrategy_cases(t, c)
+	}
+}
+
+func test_getMergeStrategy_cases(t *testing.T, testCase testCaseGetMergeStrategy) {
+	// Act
+	merge, err := getMergeStrategy(testCase.merge, testCase.appendOption, testCase.isSlice, testCase.isMap, "F")
+
+	// Assert
+	if testCase.err != "" {
+		require.EqualError(t, err, testCase.err)
+		return
+	}
+	require.Nil(t, err)
+	require.Equal(t, testCase.expected, merge)
+}
@@ -0,0 +1,157 @@
+package configuration
+
+import (
+	"errors"
+	"strconv"
+)
+
+const versionKey = "version" // reserved key with the config version of the source
+
+// Function which changes the keys of a source from one config version to the next one
+type Migration func(tree *Tree) error
+
+type migration struct {
+	from    int
+	to      int
+	migrate Migration
+}
+
+// Register the migration of sources from one config version to another
+// sources declare their version in the reserved "version" key, sources without it are in the current version
+// the current version is the highest "to" version of the registered migrations
+// from - version of the source, to - version after the migration
+// migrate - function which changes the keys of the source
+func (cr *configReader) WithMigration(from, to int, migrate Migration) *configReader {
+	if to <= from {
+		cr.data.initErrors = append(cr.data.initErrors,
+			"migration from version "+strconv.Itoa(from)+" to "+strconv.Itoa(to)+" must increase the version")
+		return cr
+	}
+	for _, m := range cr.migrations {
+		if m.from == from {
+			cr.data.initErrors = append(cr.data.initErrors,
+				"migration from version "+strconv.Itoa(from)+" is already registered")
+			return cr
+		}
+	}
+	cr.migrations = append(cr.migrations, migration{from: from, to: to, migrate: migrate})
+	return cr
+}
+
+// Read the config file and rewrite it in the layout of the current version
+// the result has the same format and the current version in the "version" key, comments are not kept
+// file - relative or absolute path to the file
+func (cr *configReader) MigrateFile(file string) ([]byte, error) {
+	fileType := cr.getFileType(file)
+	if fileType == ftUnknown {
+		return nil, errors.New(unsupportedFileTypeError(file))
+	}
+
+	source := configSource{value: file, ft: fileType, fromFile: true}
+	tree, version, err := cr.readSourceTree(source)
+	if err != nil {
+		return nil, err
+	}
+	if err = cr.migrateTree(tree, version, file); err != nil {
+		return nil, err
+	}
+	return tree.Marshal(fileType)
+}
+
+// Get the source in the layout of the current version
+// environment, key per file and struct sources have no version and are not migrated
+func (cr *configReader) migrateSource(source configSource) (configSource, error) {
+	if len(cr.migrations) == 0 || source.ft == ftEnvironment || source.ft == ftKeyPerFile || source.ft == ftStruct {
+		return source, nil
+	}
+
+	tree, version, err := cr.readSourceTree(source)
+	if err != nil {
+		return source, err
+	}
+	if version >= cr.currentVersion() {
+		return source, nil
+	}
+
+	name := source.name
+	if source.fromFile {
+		name = source.value
+	}
+	if err = cr.migrateTree(tree, version, name); err != nil {
+		return source, err
+	}
+	data, err := tree.Marshal(source.ft)
+	if err != nil {
+		return source, err
+	}
+
+	source.name = name
+	source.value = string(data)
+	source.fromFile = false
+	return source, nil
+}
+
+// Read the source alone without a user config struct
+// returns the tree of the source and its version
+func (cr *configReader) readSourceTree(source configSource) (*Tree, int, error) {
+	source.atPrefix = ""
+	source.stripPrefix = ""
+	source.priority = 0
+	source.layer = ""
+
+	reader := &configReader{
+		sources: []configSource{source},
+		options: cr.options,
+		data:    configData{initErrors: []string{}},
+	}
+	reader.options.Logger = nil
+	tree, err := reader.ReadTree()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	version := cr.currentVersion()
+	if _, ok := tree.Get(versionKey); ok {
+		version, err = tree.GetInt(versionKey)
+		if err != nil {
+			return nil, 0, errors.New("invalid config version of \"" + reader.sourceName(0) + "\": " + err.Error())
+		}
+	}
+	return tree, version, nil
+}
+
+// Apply the migrations to the tree one by one up to the current version
+// name - source name for errors
+func (cr *configReader) migrateTree(tree *Tree, version int, name string) error {
+	current := cr.currentVersion()
+	for version < current {
+		m, ok := cr.findMigration(version)
+		if !ok {
+			return errors.New("no migration from version " + strconv.Itoa(version) + " of \"" + name + "\"")
+		}
+		if err := m.migrate(tree); err != nil {
+			return errors.New("migration from version " + strconv.Itoa(m.from) + " to " + strconv.Itoa(m.to) +
+				" of \"" + name + "\" failed: " + err.Error())
+		}
+		version = m.to
+	}
+	return tree.Set(versionKey, strconv.Itoa(version))
+}
+
+func (cr *configReader) findMigration(from int) (migration, bool) {
+	for _, m := range cr.migrations {
+		if m.from == from {
+			return m, true
+		}
+	}
+	return migration{}, false
+}
+
+// Get the highest version the sources can be migrated to, 0 if there are no migrations
+func (cr *configReader) currentVersion() int {
+	current := 0
+	for _, m := range cr.migrations {
+		current = max(current, m.to)
+	}
+	return current
+}
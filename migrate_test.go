@@ -0,0 +1,179 @@
+package configuration
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type migrateConfig struct {
+	Db struct {
+		Host string `env:"host"`
+		Port int    `env:"port"`
+	} `env:"db"`
+	TimeoutMs int `env:"timeout_ms"`
+}
+
+// version 1: "address = host:port", "timeout" in seconds
+func migrateAddress(tree *Tree) error {
+	address, err := tree.GetString("address")
+	if err != nil {
+		return err
+	}
+	host, port, _ := strings.Cut(address, ":")
+	tree.Delete("address")
+	if err = tree.Set("host", host); err != nil {
+		return err
+	}
+	return tree.Set("port", port)
+}
+
+// version 2: "host" and "port" are in the "db" section, "timeout" in seconds
+func migrateSection(tree *Tree) error {
+	if err := tree.Rename("host", "db.host"); err != nil {
+		return err
+	}
+	if err := tree.Rename("port", "db.port"); err != nil {
+		return err
+	}
+	timeout, err := tree.GetInt("timeout")
+	if err != nil {
+		return err
+	}
+	tree.Delete("timeout")
+	return tree.Set("timeout_ms", strconv.Itoa(timeout*1000))
+}
+
+func Test_ReadConfig_migrate_success(t *testing.T) {
+	// Arrange
+	config := &migrateConfig{}
+	v1 := "version = 1\naddress = h1:80\ntimeout = 2"
+	v2 := `{"version": 2, "host": "h2", "port": 81, "timeout": 3}`
+	v3 := "version = 3\n[db]\nport = 82"
+
+	// Act
+	cr := NewConfigReader().
+		AddString(v1, FtEnv, "v1").
+		AddString(v2, FtJson, "v2").
+		AddString(v3, FtIni, "v3").
+		WithMigration(1, 2, migrateAddress).
+		WithMigration(2, 3, migrateSection).
+		Strict(true)
+	err := cr.ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "h2", config.Db.Host)
+	require.Equal(t, 82, config.Db.Port)
+	require.Equal(t, 3000, config.TimeoutMs)
+
+	explanation, err := cr.Explain(config)
+	require.Nil(t, err)
+	require.Equal(t, "v2", explanation[0].Source)
+}
+
+func Test_ReadConfig_migrate_without_version(t *testing.T) {
+	// Arrange
+	config := &migrateConfig{}
+
+	// Act
+	err := NewConfigReader().
+		AddString("db.host = h\ntimeout_ms = 5", FtEnv, "env").
+		WithMigration(1, 2, migrateAddress).
+		WithMigration(2, 3, migrateSection).
+		ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "h", config.Db.Host)
+	require.Equal(t, 5, config.TimeoutMs)
+}
+
+type testCaseMigrateError struct {
+	data     string
+	migrate  Migration
+	expected string
+}
+
+func Test_ReadConfig_migrate_error_cases(t *testing.T) {
+	// Arrange
+	failed := func(tree *Tree) error { return errors.New("failed") }
+	cases := []testCaseMigrateError{
+		{"version = a", migrateAddress, `invalid config version of "env": can't convert value of key version to int: strconv.Atoi: parsing "a": invalid syntax`},
+		{"version = 0", migrateAddress, `no migration from version 0 of "env"`},
+		{"version = 1", failed, `migration from version 1 to 2 of "env" failed: failed`},
+		{"version = 1", migrateAddress, `migration from version 1 to 2 of "env" failed: key address not found`},
+	}
+
+	for _, c := range cases {
+		test_ReadConfig_migrate_error_cases(t, c)
+	}
+}
+
+func test_ReadConfig_migrate_error_cases(t *testing.T, testCase testCaseMigrateError) {
+	// Arrange
+	config := &migrateConfig{}
+
+	// Act
+	err := NewConfigReader().
+		AddString(testCase.data, FtEnv, "env").
+		WithMigration(1, 2, testCase.migrate).
+		ReadConfig(config)
+
+	// Assert
+	require.EqualError(t, err, testCase.expected, testCase.data)
+}
+
+func Test_WithMigration_errors(t *testing.T) {
+	// Act
+	cr := NewConfigReader().
+		WithMigration(2, 2, migrateAddress).
+		WithMigration(1, 2, migrateAddress).
+		WithMigration(1, 3, migrateAddress)
+
+	// Assert
+	require.Equal(t, []error{
+		errors.New("migration from version 2 to 2 must increase the version"),
+		errors.New("migration from version 1 is already registered"),
+	}, cr.GetErrors())
+}
+
+func Test_MigrateFile(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "config.ini")
+	require.Nil(t, os.WriteFile(path, []byte("; old config\nversion = 1\naddress = h:80\ntimeout = 2\n"), 0o644))
+
+	// Act
+	b, err := NewConfigReader().
+		WithMigration(1, 2, migrateAddress).
+		WithMigration(2, 3, migrateSection).
+		MigrateFile(path)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "version = 3\ntimeout_ms = 2000\n\n[db]\nhost = h\nport = 80\n", string(b))
+}
+
+func Test_Tree_Rename(t *testing.T) {
+	// Arrange
+	tree, err := NewConfigReader().AddString("host = h\ndb.port = 1\ndb.name = n\nport = 2", FtEnv, "env").ReadTree()
+	require.Nil(t, err)
+
+	// Act
+	errMissing := tree.Rename("missing", "other")
+	errDb := tree.Rename("db", "database")
+	errPort := tree.Rename("port", "database.port")
+
+	// Assert
+	require.EqualError(t, errMissing, "key missing not found")
+	require.Nil(t, errDb)
+	require.Nil(t, errPort)
+	require.Equal(t, []string{"database.name", "database.port", "host"}, tree.Keys(""))
+	port, _ := tree.GetInt("database.port")
+	require.Equal(t, 2, port)
+}
@@ -0,0 +1,74 @@
+package configuration
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Get the key name of the field without env tag
+// name - Go field name
+func (cr *configReader) fieldKeyName(name string) string {
+	switch cr.options.Naming {
+	case NamingSnakeCase:
+		return strings.ToLower(strings.Join(splitFieldName(name), "_"))
+	case NamingKebabCase:
+		return strings.ToLower(strings.Join(splitFieldName(name), "-"))
+	case NamingCamelCase:
+		words := splitFieldName(name)
+		for i, word := range words {
+			word = strings.ToLower(word)
+			if i > 0 {
+				word = strings.ToUpper(word[:1]) + word[1:]
+			}
+			words[i] = word
+		}
+		return strings.Join(words, "")
+	case NamingLower:
+		if cr.options.CaseSensitive {
+			return strings.ToLower(name)
+		}
+	}
+	// NamingLower and NamingExact, key names are lowercased with the prefix unless CaseSensitive is set
+	return name
+}
+
+// Split the Go field name into words, e.g. "HTTPServerID2" into "HTTP", "Server", "ID2"
+func splitFieldName(name string) []string {
+	words := []string{}
+	runes := []rune(name)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if runes[i] == '_' {
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+		if !unicode.IsUpper(runes[i]) || i == start {
+			continue
+		}
+		prev := runes[i-1]
+		nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && nextLower {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// Get the key name from json or yaml tag of the field, "-" if the field is ignored by them
+func fallbackTagName(field reflect.StructField) string {
+	for _, key := range []string{"json", "yaml"} {
+		if tag, ok := field.Tag.Lookup(key); ok {
+			name, _, _ := strings.Cut(tag, ",")
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,101 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCaseFieldKeyName struct {
+	naming   namingType
+	name     string
+	expected string
+}
+
+func Test_fieldKeyName_cases(t *testing.T) {
+	// Arrange
+	cases := []testCaseFieldKeyName{
+		{NamingLower, "MaxIdleConns", "MaxIdleConns"},
+		{NamingSnakeCase, "MaxIdleConns", "max_idle_conns"},
+		{NamingSnakeCase, "HTTPServerID2", "http_server_id2"},
+		{NamingSnakeCase, "UserID", "user_id"},
+		{NamingSnakeCase, "Max_Conns", "max_conns"},
+		{NamingSnakeCase, "A", "a"},
+		{NamingKebabCase, "MaxIdleConns", "max-idle-conns"},
+		{NamingKebabCase, "TLSConfig", "tls-config"},
+		{NamingCamelCase, "MaxIdleConns", "maxIdleConns"},
+		{NamingCamelCase, "HTTPServer", "httpServer"},
+		{NamingExact, "MaxIdleConns", "MaxIdleConns"},
+	}
+
+	for _, c := range cases {
+		test_fieldKeyName_cases(t, c)
+	}
+}
+
+func test_fieldKeyName_cases(t *testing.T, testCase testCaseFieldKeyName) {
+	// Arrange
+	cr := NewConfigReader().WithNaming(testCase.naming)
+
+	// Act
+	name := cr.fieldKeyName(testCase.name)
+
+	// Assert
+	require.Equal(t, testCase.expected, name, testCase.name)
+}
+
+func Test_ReadConfig_naming(t *testing.T) {
+	// Arrange
+	config := &struct {
+		MaxIdleConns int
+		Port         int `env:"listen_port"`
+		DbServer     struct {
+			HostName string
+		}
+	}{}
+	data := "max_idle_conns = 5\nlisten_port = 80\n[db_server]\nhost_name = h"
+
+	// Act
+	err := NewConfigReader().
+		AddString(data, FtIni, "ini").
+		WithNaming(NamingSnakeCase).
+		Strict(true).
+		ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, 5, config.MaxIdleConns)
+	require.Equal(t, 80, config.Port)
+	require.Equal(t, "h", config.DbServer.HostName)
+}
+
+func Test_ReadConfig_fallback_tags(t *testing.T) {
+	// Arrange
+	config := &struct {
+		MaxIdleConns int    `json:"max_idle_conns,omitempty"`
+		Timeout      string `yaml:"timeout_str"`
+		Host         string `json:"host" env:"address"`
+		Ignored      string `json:"-"`
+		IdleTimeout  string `json:",omitempty"`
+		Db           struct {
+			Name string `json:"db_name"`
+		} `json:"database"`
+	}{}
+	data := `{"max_idle_conns": 5, "timeout_str": "1s", "address": "h", "idle-timeout": "2s", "database": {"db_name": "n"}}`
+
+	// Act
+	err := NewConfigReader().
+		AddString(data, FtJson, "json").
+		FallbackTags(true).
+		WithNaming(NamingKebabCase).
+		ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, 5, config.MaxIdleConns)
+	require.Equal(t, "1s", config.Timeout)
+	require.Equal(t, "h", config.Host)
+	require.Equal(t, "", config.Ignored)
+	require.Equal(t, "2s", config.IdleTimeout)
+	require.Equal(t, "n", config.Db.Name)
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"os"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -29,6 +31,43 @@ func (cr *configReader) getFileType(name string) formatType {
 	return ftUnknown
 }
 
+func (cr *configReader) readSources(it intermediateTree, si []structInfo) error {
+	cr.data.lastSources = cr.orderedSources()
+	cr.data.warnings = nil
+	for i, source := range cr.data.lastSources {
+		source, err := cr.migrateSource(source)
+		if err != nil {
+			return err
+		}
+		cr.data.currentFormat = source.ft
+		cr.data.atPrefix = cr.keyCase(source.atPrefix)
+		cr.data.stripPrefix = cr.keyCase(source.stripPrefix)
+		if source.ft == ftEnvironment {
+			if cr.data.schemaless {
+				err = cr.readEnvironment(it, getTreeStructInfo(it), i)
+			} else {
+				err = cr.readEnvironment(it, si, i)
+			}
+		} else if source.ft == ftKeyPerFile {
+			err = cr.readKeyPerFileDir(source, it, si, i)
+		} else if source.ft == ftStruct {
+			err = cr.readStruct(source, it, si, i)
+		} else if source.fromFile {
+			err = cr.readConfigFile(source, it, si, i)
+		} else {
+			err = cr.readConfigString(source, it, si, i)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	cr.data.atPrefix = ""
+	cr.data.stripPrefix = ""
+	cr.applyAliases(it, si)
+	cr.applyUnset(it)
+	return nil
+}
+
 // https://stackoverflow.com/questions/37135193/how-to-set-default-values-in-go-structs
 // https://go.dev/play/p/rFql2x0Klm4
 func (cr *configReader) getStructInfo(userConfig interface{}, fieldPrefix, namePrefix string) ([]structInfo, error) {
@@ -39,30 +78,45 @@ func (cr *configReader) getStructInfo(userConfig interface{}, fieldPrefix, nameP
 		return nil, errors.New("pass your config struct as a pointer")
 	}
 
-	info := []structInfo{}
-
 	v := reflect.ValueOf(userConfig).Elem()
-	t := v.Type()
-
 	if v.Kind() != reflect.Struct {
 		return nil, errors.New("user config must be a struct")
 	}
 
+	si, err := cr.getStructValueInfo(v, fieldPrefix, namePrefix)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkAliases(si); err != nil {
+		return nil, err
+	}
+	return si, nil
+}
+
+func (cr *configReader) getStructValueInfo(v reflect.Value, fieldPrefix, namePrefix string) ([]structInfo, error) {
+	info := []structInfo{}
+	t := v.Type()
+
+	embedded := map[string]string{} // key name - field which has it
+	squashed := map[string]bool{}   // embedded and squashed fields
 	for i := 0; i < t.NumField(); i++ {
 		var field = t.Field(i)
 		var fieldType = field.Type
 
 		if fieldType.Kind() == reflect.Struct &&
-			fieldType.String() != "time.Time" {
+			fieldType.String() != "time.Time" && fieldType != rawSectionType {
 			var keyName string
 			var err error
-			if envData, ok := cr.hasEnvData(field.Tag.Get("env")); ok {
-				useParser := false
-				keyName, _, _, useParser, err = cr.getTagData(envData, field)
+			squash := false
+			if envData, ok := cr.getEnvData(field); ok {
+				var tag tagData
+				tag, err = cr.getTagData(envData, field)
 				if err != nil {
 					return nil, err
 				}
-				if useParser {
+				keyName = tag.keyName
+				squash = tag.squash || field.Anonymous && envData == ""
+				if tag.useParser {
 					info, err = cr.appendStructInfo(info, fieldType, field, v, i, envData, namePrefix, fieldPrefix)
 					if err != nil {
 						return nil, err
@@ -72,35 +126,73 @@ func (cr *configReader) getStructInfo(userConfig interface{}, fieldPrefix, nameP
 				}
 			}
 
-			subInfo, err := cr.getStructInfo(v.Field(i).Addr().Interface(), fieldPrefix+field.Name+".", namePrefix+keyName+".")
+			subPrefix := namePrefix + keyName + "."
+			if squash {
+				subPrefix = namePrefix
+			}
+			subInfo, err := cr.getStructValueInfo(v.Field(i), fieldPrefix+field.Name+".", subPrefix)
 			if err != nil {
 				return nil, err
 			}
+			squashed[field.Name] = squash
+			if err = checkEmbeddedKeys(embedded, squashed, subInfo, field.Name); err != nil {
+				return nil, err
+			}
 			info = append(info, subInfo...)
-		} else if envData, ok := cr.hasEnvData(field.Tag.Get("env")); ok {
+		} else if envData, ok := cr.getEnvData(field); ok {
 			if !v.Field(i).CanSet() {
 				continue
 			}
-			var err error
-			info, err = cr.appendStructInfo(info, fieldType, field, v, i, envData, namePrefix, fieldPrefix)
+			fieldInfo, err := cr.appendStructInfo(nil, fieldType, field, v, i, envData, namePrefix, fieldPrefix)
 			if err != nil {
 				return nil, err
 			}
+			if err = checkEmbeddedKeys(embedded, squashed, fieldInfo, field.Name); err != nil {
+				return nil, err
+			}
+			info = append(info, fieldInfo...)
 		}
 	}
 
 	return info, nil
 }
 
+// Check that the keys of the embedded or squashed struct are not in the other fields at the same level
+// and the keys of the field are not in the embedded or squashed structs checked before
+// embedded - key names of the fields checked before and the fields which have them
+// squashed - embedded and squashed fields
+func checkEmbeddedKeys(embedded map[string]string, squashed map[string]bool, si []structInfo, fieldName string) error {
+	for _, s := range si {
+		if other, ok := embedded[s.keyName]; ok && other != fieldName && (squashed[other] || squashed[fieldName]) {
+			return errors.New("key \"" + s.keyName + "\" is in fields " + other + " and " + fieldName)
+		}
+		embedded[s.keyName] = fieldName
+	}
+	return nil
+}
+
 func (cr *configReader) appendStructInfo(info []structInfo,
 	fieldType reflect.Type, field reflect.StructField, v reflect.Value,
 	i int, envData, namePrefix, fieldPrefix string) ([]structInfo, error) {
 
-	keyName, isRequired, appendToSlice, useParser, err := cr.getTagData(envData, field)
+	tag, err := cr.getTagData(envData, field)
 	if err != nil {
 		return nil, err
 	}
 
+	if isRawType(fieldType) {
+		return append(info, structInfo{
+			fieldName:   fieldPrefix + field.Name,
+			fieldType:   fieldType,
+			field:       v.Field(i),
+			keyName:     cr.keyCase(namePrefix + tag.keyName),
+			isRequired:  tag.isRequired,
+			isSecret:    tag.isSecret,
+			description: field.Tag.Get("desc"),
+			isRaw:       true,
+		}), nil
+	}
+
 	isPointer := fieldType.Kind() == reflect.Ptr
 
 	sep := field.Tag.Get("sep")
@@ -138,6 +230,35 @@ func (cr *configReader) appendStructInfo(info []structInfo,
 		}
 	}
 
+	if tag.remain {
+		if !isMap || field.Type.Key().Kind() != reflect.String ||
+			fieldType.Kind() != reflect.String && fieldType.Kind() != reflect.Interface {
+			return nil, errors.New("field " + fieldPrefix + field.Name + " with remain option must be map[string]string or map[string]interface{}")
+		}
+		return append(info, structInfo{
+			fieldName:  fieldPrefix + field.Name,
+			fieldType:  fieldType,
+			field:      v.Field(i),
+			keyName:    cr.keyCase(namePrefix) + remainKey,
+			separator:  sep,
+			separator2: sep2,
+			isMap:      true,
+			isSecret:   tag.isSecret,
+			isRemain:   true,
+		}), nil
+	}
+
+	merge, err := getMergeStrategy(field.Tag.Get("merge"), tag.append,
+		isSlice || isArray, isMap, fieldPrefix+field.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := make([]string, 0, len(tag.aliases))
+	for _, alias := range tag.aliases {
+		aliases = append(aliases, cr.keyCase(namePrefix+alias))
+	}
+
 	def, ok := field.Tag.Lookup("def")
 	if !ok {
 		if isPointer || isSlice {
@@ -148,61 +269,98 @@ func (cr *configReader) appendStructInfo(info []structInfo,
 	}
 
 	info = append(info, structInfo{
-		fieldName:  fieldPrefix + field.Name,
-		fieldType:  fieldType,
-		field:      v.Field(i),
-		keyName:    strings.ToLower(namePrefix + keyName),
-		defValue:   def,
-		isRequired: isRequired,
-		useParser:  useParser,
-		separator:  sep,
-		separator2: sep2,
-		isSlice:    isSlice || isArray,
-		isMap:      isMap,
-		isPointer:  isPointer,
-		append:     appendToSlice,
-		size:       arraySize,
+		fieldName:   fieldPrefix + field.Name,
+		fieldType:   fieldType,
+		field:       v.Field(i),
+		keyName:     cr.keyCase(namePrefix + tag.keyName),
+		defValue:    def,
+		isRequired:  tag.isRequired,
+		useParser:   tag.useParser,
+		separator:   sep,
+		separator2:  sep2,
+		isSlice:     isSlice || isArray,
+		isMap:       isMap,
+		isPointer:   isPointer,
+		merge:       merge,
+		size:        arraySize,
+		secretFile:  tag.secretFile,
+		isSecret:    tag.isSecret || tag.secretFile,
+		description: field.Tag.Get("desc"),
+		aliases:     aliases,
+		deprecated:  tag.deprecated,
 	})
 
 	return info, nil
 }
 
-func (cr *configReader) hasEnvData(envData string) (string, bool) {
+// Get the env tag of the field, or the name from json and yaml tags if FallbackTags is set
+// returns false if the field is ignored
+func (cr *configReader) getEnvData(field reflect.StructField) (string, bool) {
+	envData, ok := field.Tag.Lookup("env")
+	if !ok && cr.options.FallbackTags {
+		envData = fallbackTagName(field)
+	}
 	return envData, envData != ignoreField
 }
 
-func (cr *configReader) getTagData(envData string, field reflect.StructField) (string, bool, bool, bool, error) {
-	keyName := ""
-	isRequired := false
-	appendToSlice := false
-	useParser := false
+func (cr *configReader) getTagData(envData string, field reflect.StructField) (tagData, error) {
+	tag := tagData{}
 	if envData == "" {
-		keyName = field.Name
+		tag.keyName = cr.fieldKeyName(field.Name)
 	} else {
-		split := strings.Split(strings.ToLower(envData), ",")
-		if len(split) == 0 || strings.TrimSpace(split[0]) == "" {
-			return "", false, false, false, errors.New("env tag is empty for field " + field.Name)
+		split := strings.Split(envData, ",")
+		if len(split) == 0 || strings.TrimSpace(split[0]) == "" &&
+			!slices.Contains(split[1:], "remain") && !slices.Contains(split[1:], "squash") {
+			return tagData{}, errors.New("env tag is empty for field " + field.Name)
 		}
 		if strings.ContainsAny(split[0], ".") {
-			return "", false, false, false, errors.New("env tag contains invalid characters for field " + field.Name)
+			return tagData{}, errors.New("env tag contains invalid characters for field " + field.Name)
 		}
-		keyName = split[0]
-		for _, s := range split[1:] {
+		tag.keyName = split[0]
+		for _, option := range split[1:] {
+			s := strings.ToLower(option)
 			if s == "required" {
-				isRequired = true
+				tag.isRequired = true
 			} else if s == "append" {
-				appendToSlice = true
+				tag.append = true
 			} else if s == "useparser" {
-				useParser = true
+				tag.useParser = true
+			} else if s == "secretfile" {
+				tag.secretFile = true
+			} else if s == "secret" {
+				tag.isSecret = true
+			} else if s == "remain" {
+				tag.remain = true
+			} else if strings.HasPrefix(s, "alias=") {
+				tag.aliases = getAliases(option)
+			} else if s == "deprecated" {
+				tag.deprecated = true
+			} else if s == "squash" {
+				tag.squash = true
 			}
 		}
 	}
 
-	return keyName, isRequired, appendToSlice, useParser, nil
+	return tag, nil
 }
 
 func (cr *configReader) setValues(it intermediateTree, si []structInfo) error {
 	for _, info := range si {
+		if info.isRemain {
+			cr.setRemainValue(info, it[info.keyName])
+			continue
+		} else if info.isRaw {
+			if err := cr.setRawValue(info, it[info.keyName]); err != nil {
+				return err
+			}
+			continue
+		}
+		def, err := cr.expandValue(info.defValue, it, si, []string{info.keyName})
+		if err != nil {
+			return errors.New("can't expand default value of field " + info.fieldName + ": " + err.Error())
+		}
+		info.defValue = def
+
 		data, ok := it[info.keyName]
 		if ok || info.defValue != "" {
 			str := ""
@@ -213,15 +371,9 @@ func (cr *configReader) setValues(it intermediateTree, si []structInfo) error {
 			if ok {
 				var value interface{}
 				if info.isSlice {
-					if info.append {
-						for _, d := range data {
-							strSlice = append(strSlice, d.value.([]string)...)
-							vType = d.valueType
-						}
-					} else if len(data) > 0 {
-						last := data[len(data)-1]
-						strSlice = last.value.([]string)
-						vType = last.valueType
+					strSlice, vType = mergeSliceData(info, data)
+					if len(strSlice) == 1 && strSlice[0] == nilDefault && !info.isPointer {
+						vType = vtNull
 					}
 					if len(strSlice) == 0 && info.isRequired && (info.defValue == "" || info.defValue == nilDefault) {
 						return errors.New("required field " + info.fieldName + " is empty")
@@ -237,18 +389,7 @@ func (cr *configReader) setValues(it intermediateTree, si []structInfo) error {
 						}
 					}
 				} else if info.isMap {
-					if info.append {
-						for _, d := range data {
-							for k, v := range d.value.(map[string]string) {
-								strMap[k] = v
-								vType = d.valueType
-							}
-						}
-					} else if len(data) > 0 {
-						last := data[len(data)-1]
-						strMap = last.value.(map[string]string)
-						vType = last.valueType
-					}
+					strMap, vType = mergeMapData(info, data)
 					if len(strMap) == 0 && info.isRequired && (info.defValue == "" || info.defValue == nilDefault) {
 						return errors.New("required field " + info.fieldName + " is empty")
 					}
@@ -328,16 +469,24 @@ func (cr *configReader) setValues(it intermediateTree, si []structInfo) error {
 			if (info.isPointer || info.isSlice) && info.size == 0 && info.defValue == nilDefault && isEMpty ||
 				(info.isPointer || info.isSlice) && info.size == 0 && vType == vtNull {
 				continue
-			} else if info.isSlice && info.size > 0 && isEMpty {
-				if len(strSlice) == 0 {
-					continue
-				} else if len(strSlice) > info.size {
-					return errors.New("field " + info.fieldName + " has more values than allowed: " + strings.Join(strSlice, ","))
-				}
-			} else if info.isMap && isEMpty && len(strMap) == 0 && (info.defValue == "" || info.defValue == nilDefault) {
+			} else if info.isSlice && info.size > 0 && isEMpty && len(strSlice) == 0 {
+				continue
+			} else if info.isMap && isEMpty && len(strMap) == 0 && (info.defValue == "" || info.defValue == nilDefault) ||
+				info.isMap && vType == vtNull {
 				continue
 			}
 
+			if info.isSlice && info.size > 0 && len(strSlice) > info.size {
+				return errors.New("field " + info.fieldName + " has more values than allowed: " + cr.redactIfSecret(info, strings.Join(strSlice, ",")))
+			}
+
+			if isEMpty && !info.isSlice && !info.isMap {
+				str, _, err = cr.resolveSecretValue(info, str)
+				if err != nil {
+					return err
+				}
+			}
+
 			err := cr.setFieldValue(info, str, strSlice, strMap, vType)
 			if err != nil {
 				return err
@@ -456,19 +605,27 @@ func (cr *configReader) readToNextLine(r *bufio.Reader, allowMultiline bool) err
 	return err
 }
 
+func (cr *configReader) findField(r *bufio.Reader, it intermediateTree, si []structInfo,
+	name, key string, isSlice, allowMultiline bool, sourceId int) (bool, structInfo, bool, error) {
+	if cr.data.schemaless {
+		info, err := cr.getSchemalessInfo(it, name, key != "", isSlice, sourceId)
+		return err == nil, info, false, err
+	}
+	return cr.findFieldByName(r, si, name, allowMultiline)
+}
+
 func (cr *configReader) findFieldByName(r *bufio.Reader, si []structInfo, name string, allowMultiline bool) (bool, structInfo, bool, error) {
-	found := false
-	foundInfo := structInfo{}
 	continue_ := false
 
-	for _, s := range si {
-		if s.keyName == name {
-			found = true
-			foundInfo = s
-			break
-		}
+	found, foundInfo := findStructInfo(si, name)
+	if !found {
+		found, foundInfo = findRawInfo(si, name)
 	}
 	if !found {
+		found, foundInfo = findRemainInfo(si, name)
+	}
+	if !found {
+		cr.addUnknownKey(name)
 		if err := cr.readToNextLine(r, allowMultiline); err != nil {
 			if err == io.EOF {
 				continue_ = false
@@ -482,12 +639,54 @@ func (cr *configReader) findFieldByName(r *bufio.Reader, si []structInfo, name s
 	return found, foundInfo, continue_, nil
 }
 
+// Remember the key which is not in the struct info, used by the schema validation
+func (cr *configReader) addUnknownKey(name string) {
+	if !cr.data.trackUnknownKeys || name == versionKey && len(cr.migrations) > 0 {
+		return
+	}
+	for _, k := range cr.data.unknownKeys {
+		if k.name == name || strings.HasPrefix(name, k.name+".") {
+			return
+		}
+	}
+	cr.data.unknownKeys = append(cr.data.unknownKeys, unknownKey{name: name, source: cr.data.currentFile, line: cr.data.keyLine})
+}
+
+func (cr *configReader) unknownKeysError() error {
+	errs := make([]error, 0, len(cr.data.unknownKeys))
+	for _, k := range cr.data.unknownKeys {
+		location := "\"" + k.source + "\""
+		if k.line > 0 {
+			location += " (line " + strconv.Itoa(k.line) + ")"
+		}
+		errs = append(errs, errors.New("unknown key \""+k.name+"\" in "+location))
+	}
+	return errors.Join(errs...)
+}
+
+func keyPrefix(prefix string) string {
+	prefix = strings.Trim(prefix, ".")
+	if prefix == "" {
+		return ""
+	}
+	return prefix + "."
+}
+
+func findStructInfo(si []structInfo, name string) (bool, structInfo) {
+	for _, s := range si {
+		if s.hasName(name) && !s.isRemain {
+			return true, s
+		}
+	}
+	return false, structInfo{}
+}
+
 func (cr *configReader) findFieldByJsonName(si []structInfo, name string) (bool, structInfo) {
 	found := false
 	foundInfo := structInfo{}
 
 	for _, s := range si {
-		if s.keyName == name || strings.HasPrefix(s.keyName, name+".") {
+		if !s.isRemain && (s.hasName(name) || s.hasNamePrefix(name)) {
 			found = true
 			foundInfo = s
 			break
@@ -629,6 +629,23 @@ func test_setValue_default_error(t *testing.T, testCase testCaseSetValueDefaulEr
 	require.Equal(t, testCase.err, err.Error())
 }
 
+func Test_ReadConfig_array_overflow(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Ids  [2]int    `env:"ids"`
+		Pins [1]string `env:"pins,secret"`
+	}{}
+	cr := NewConfigReader().AddString("ids = 1,2,3", FtEnv, "env")
+
+	// Act
+	err := cr.ReadConfig(config)
+	errSecret := NewConfigReader().AddString("pins = a,b", FtEnv, "env").ReadConfig(config)
+
+	// Assert
+	require.EqualError(t, err, "field Ids has more values than allowed: 1,2,3")
+	require.EqualError(t, errSecret, "field Pins has more values than allowed: "+redactedValue)
+}
+
 func Test_setValue_useParser(t *testing.T) {
 	// Arrange
 	type subType struct {
@@ -811,6 +828,97 @@ func test_getStructInfo_ignoreNotSettable(t *testing.T, config interface{}) {
 	require.Empty(t, si)
 }
 
+type embeddedBaseConfig struct {
+	Timeout int `env:"timeout"`
+	Retries int `env:"retries"`
+}
+
+type embeddedLogConfig struct {
+	Level string `env:"level"`
+}
+
+type EmbeddedDbConfig struct {
+	embeddedLogConfig
+	Host string `env:"host"`
+}
+
+func Test_ReadConfig_embedded(t *testing.T) {
+	// Arrange
+	config := &struct {
+		embeddedBaseConfig
+		EmbeddedDbConfig `env:"db"`
+		Cache            struct {
+			embeddedBaseConfig
+			Size int `env:"size"`
+		} `env:"cache"`
+		Log embeddedLogConfig `env:",squash"`
+	}{}
+	data := "timeout = 1\nretries = 2\nlevel = info\n" +
+		"[db]\nhost = h\nlevel = debug\n[cache]\ntimeout = 3\nsize = 10"
+
+	// Act
+	err := NewConfigReader().AddString(data, FtIni, "ini").Strict(true).ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, 1, config.Timeout)
+	require.Equal(t, 2, config.Retries)
+	require.Equal(t, "h", config.EmbeddedDbConfig.Host)
+	require.Equal(t, "debug", config.EmbeddedDbConfig.Level)
+	require.Equal(t, 3, config.Cache.Timeout)
+	require.Equal(t, 10, config.Cache.Size)
+	require.Equal(t, "info", config.Log.Level)
+}
+
+type testCaseEmbeddedCollision struct {
+	config interface{}
+	err    string
+}
+
+func Test_getStructInfo_embedded_collision_cases(t *testing.T) {
+	// Arrange
+	cases := []testCaseEmbeddedCollision{
+		{&struct {
+			embeddedBaseConfig
+			Other struct {
+				Retries int `env:"retries"`
+			} `env:",squash"`
+		}{}, `key "retries" is in fields embeddedBaseConfig and Other`},
+		{&struct {
+			embeddedBaseConfig
+			Timeout int `env:"timeout"`
+		}{}, `key "timeout" is in fields embeddedBaseConfig and Timeout`},
+		{&struct {
+			Timeout int                `env:"timeout"`
+			Base    embeddedBaseConfig `env:",squash"`
+		}{}, `key "timeout" is in fields Timeout and Base`},
+		{&struct {
+			Log embeddedLogConfig `env:",squash"`
+			Db  struct {
+				embeddedLogConfig
+				Level string `env:"level"`
+			} `env:"db"`
+		}{}, `key "db.level" is in fields embeddedLogConfig and Level`},
+	}
+
+	// Act & Assert
+	for _, c := range cases {
+		t.Log("Test case:", c.err)
+		test_getStructInfo_embedded_collision(t, c)
+	}
+}
+
+func test_getStructInfo_embedded_collision(t *testing.T, testCase testCaseEmbeddedCollision) {
+	// Arrange
+	cr := NewConfigReader()
+
+	// Act
+	_, err := cr.getStructInfo(testCase.config, "", "")
+
+	// Assert
+	require.EqualError(t, err, testCase.err)
+}
+
 type testCaseGetTagDataSuccess struct {
 	data         string
 	field        reflect.StructField
@@ -852,14 +960,14 @@ func test_getTagData_success_cases(t *testing.T, testCase testCaseGetTagDataSucc
 	cr := &configReader{}
 
 	// Act
-	name, required, append, useParser, err := cr.getTagData(testCase.data, testCase.field)
+	tag, err := cr.getTagData(testCase.data, testCase.field)
 
 	// Assert
 	require.Nil(t, err)
-	require.Equal(t, testCase.expName, name)
-	require.Equal(t, testCase.expRequired, required)
-	require.Equal(t, testCase.expAppend, append)
-	require.Equal(t, testCase.expUseParser, useParser)
+	require.Equal(t, testCase.expName, tag.keyName)
+	require.Equal(t, testCase.expRequired, tag.isRequired)
+	require.Equal(t, testCase.expAppend, tag.append)
+	require.Equal(t, testCase.expUseParser, tag.useParser)
 }
 
 type testCaseGetTagDataError struct {
@@ -889,7 +997,7 @@ func test_getTagData_error_cases(t *testing.T, testCase testCaseGetTagDataError)
 	cr := &configReader{}
 
 	// Act
-	_, _, _, _, err := cr.getTagData(testCase.data, testCase.field)
+	_, err := cr.getTagData(testCase.data, testCase.field)
 
 	// Assert
 	require.Equal(t, testCase.err, err.Error())
@@ -0,0 +1,272 @@
+package configuration
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+)
+
+var (
+	rawSectionType = reflect.TypeOf(RawSection{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// Configuration section kept as text to be decoded later, e.g. by a plugin with its own config struct
+// json sections keep the text without comments, env and ini sections are re-serialized
+type RawSection struct {
+	// Format of the data: FtEnv, FtIni or FtJson
+	Format formatType
+	// Text of the section with the keys relative to the section name
+	Data []byte
+
+	// options of the reader which read the section
+	naming        namingType
+	caseSensitive bool
+	fallbackTags  bool
+}
+
+// Decode the section into the user config struct with the same rules as ReadConfig
+// key names follow the Naming, CaseSensitive and FallbackTags options of the reader which read the section
+// target - pointer to the user config struct
+func (s RawSection) Decode(target interface{}) error {
+	return NewConfigReader().
+		WithNaming(s.naming).
+		CaseSensitive(s.caseSensitive).
+		FallbackTags(s.fallbackTags).
+		AddString(string(s.Data), s.Format, "raw section").
+		ReadConfig(target)
+}
+
+// Value of the field with RawSection or json.RawMessage type in one source
+type rawValue struct {
+	format formatType
+	text   string        // json text or value of the key itself
+	items  []marshalItem // keys under the section from env and ini sources
+}
+
+func isRawType(fieldType reflect.Type) bool {
+	return fieldType == rawSectionType || fieldType == rawMessageType
+}
+
+func isJsonSection(text []byte) bool {
+	text = bytes.TrimSpace(text)
+	return len(text) > 0 && (text[0] == '{' || text[0] == '[') && json.Valid(text)
+}
+
+// Find the raw field which contains the key
+func findRawInfo(si []structInfo, name string) (bool, structInfo) {
+	for _, s := range si {
+		if s.isRaw && strings.HasPrefix(name, s.keyName+".") {
+			return true, s
+		}
+	}
+	return false, structInfo{}
+}
+
+// Add the value of the key inside the raw section
+// name - full key name
+// key - map key for "name[key]", isSlice - for "name[]"
+func (cr *configReader) addRawValue(info structInfo, it intermediateTree, name, value, key string, isSlice bool, sourceId int) {
+	var raw *rawValue
+	for _, d := range it[info.keyName] {
+		if d.source == sourceId {
+			raw = d.value.(*rawValue)
+		}
+	}
+	if raw == nil {
+		format := cr.data.currentFormat
+		if format != FtJson && format != FtIni {
+			format = FtEnv
+		}
+		raw = &rawValue{format: format}
+		it[info.keyName] = append(it[info.keyName], intermediateData{source: sourceId, value: raw, valueType: vtAny, line: cr.data.keyLine})
+	}
+
+	name = strings.TrimPrefix(name, info.keyName)
+	if name == "" {
+		raw.text = value
+		return
+	}
+	name = name[1:]
+
+	index := -1
+	for i, item := range raw.items {
+		if item.key == name {
+			index = i
+		}
+	}
+	if index < 0 {
+		raw.items = append(raw.items, marshalItem{key: name, valueType: vtAny, separator: sepDefault, separator2: sep2Default, isNullable: true})
+		index = len(raw.items) - 1
+	}
+
+	item := &raw.items[index]
+	if key != "" {
+		values, ok := item.value.(map[string]string)
+		if !ok {
+			values = map[string]string{}
+		}
+		values[key] = value
+		item.value = values
+		item.isMap = true
+	} else if isSlice {
+		values, _ := item.value.([]string)
+		item.value = append(values, value)
+		item.isMap = false
+	} else {
+		item.value = value
+		item.isMap = false
+	}
+}
+
+// Read json object or array till the end and return its text without comments
+// opener - "{" or "[" which is already read
+func (cr *configReader) readJsonRaw(r *bufio.Reader, opener string) (string, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(opener)
+	depth := 1
+	isQuoted := false
+	escape := false
+	prev := ' '
+	comment := ""
+
+	for depth > 0 {
+		rn, _, err := r.ReadRune()
+		if err != nil {
+			if err == io.EOF {
+				return "", errors.New("unexpected end of file " + cr.currentPointInfo())
+			}
+			return "", err
+		}
+		cr.data.currentPos++
+		if rn == '\n' || rn == '\r' {
+			cr.checkNewLine(rn)
+		}
+
+		if comment == "//" {
+			if rn == '\n' {
+				comment = ""
+				buffer.WriteRune(rn)
+			}
+		} else if comment == "/*" {
+			if prev == '*' && rn == '/' {
+				comment = ""
+				rn = ' '
+			}
+		} else if isQuoted {
+			buffer.WriteRune(rn)
+			if escape {
+				escape = false
+			} else if rn == '\\' {
+				escape = true
+			} else if rn == '"' {
+				isQuoted = false
+			}
+		} else if prev == '/' && (rn == '/' || rn == '*') {
+			// the comment is not a part of the text, remove the slash which opens it
+			buffer.Truncate(buffer.Len() - 1)
+			comment = "/" + string(rn)
+			rn = ' '
+		} else {
+			buffer.WriteRune(rn)
+			if rn == '"' {
+				isQuoted = true
+			} else if rn == '{' || rn == '[' {
+				depth++
+			} else if rn == '}' || rn == ']' {
+				depth--
+			}
+		}
+		prev = rn
+	}
+	return buffer.String(), nil
+}
+
+// Set the raw field from the last source (or the first one if RewriteValues is false)
+func (cr *configReader) setRawValue(info structInfo, data []intermediateData) error {
+	if len(data) == 0 {
+		if info.isRequired {
+			return errors.New("required field " + info.fieldName + " value is missing")
+		}
+		info.field.Set(reflect.Zero(info.field.Type()))
+		return nil
+	}
+
+	d := data[len(data)-1]
+	if !cr.options.RewriteValues {
+		d = data[0]
+	}
+	raw := d.value.(*rawValue)
+
+	format := raw.format
+	if info.fieldType == rawMessageType {
+		format = FtJson
+	}
+	text := []byte(raw.text)
+	if len(raw.items) > 0 {
+		items := make([]marshalItem, len(raw.items))
+		for i, item := range raw.items {
+			item.valueType = inferItemValueType(item.value, item.valueType)
+			items[i] = item
+		}
+		var err error
+		text, err = marshalItems(items, format)
+		if err != nil {
+			return errors.New("can't write raw field " + info.fieldName + ": " + err.Error())
+		}
+	} else if raw.format != FtJson && isJsonSection(text) {
+		// the whole section is passed as json text, e.g. in an environment variable
+		format = FtJson
+	} else if raw.format != FtJson && format == FtJson {
+		text = []byte(quoteJsonString(raw.text))
+	}
+
+	if info.fieldType == rawMessageType {
+		info.field.Set(reflect.ValueOf(json.RawMessage(text)))
+	} else {
+		info.field.Set(reflect.ValueOf(RawSection{
+			Format:        format,
+			Data:          text,
+			naming:        cr.options.Naming,
+			caseSensitive: cr.options.CaseSensitive,
+			fallbackTags:  cr.options.FallbackTags,
+		}))
+	}
+	return nil
+}
+
+// Get the raw field value and its format
+func rawFieldData(info structInfo) ([]byte, formatType) {
+	if info.fieldType == rawMessageType {
+		return info.field.Bytes(), FtJson
+	}
+	section := info.field.Interface().(RawSection)
+	return section.Data, section.Format
+}
+
+// Get an item for every key of the raw section, the section is read back without a struct
+// a section which can't be read as a config is written as a single string value
+func (cr *configReader) getRawMarshalItems(info structInfo) []marshalItem {
+	data, format := rawFieldData(info)
+	if len(data) == 0 {
+		return nil
+	}
+	item := marshalItem{key: info.keyName, value: cr.redactIfSecret(info, string(data)), valueType: vtString}
+	if info.isSecret {
+		return []marshalItem{item}
+	}
+
+	tree, err := NewConfigReader().AddString(string(data), format, "raw section").ReadTree()
+	if err != nil {
+		return []marshalItem{item}
+	}
+	items := tree.marshalItems()
+	for i := range items {
+		items[i].key = info.keyName + "." + items[i].key
+	}
+	return items
+}
@@ -0,0 +1,156 @@
+package configuration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type rawPluginConfig struct {
+	Name  string   `env:"name"`
+	Port  int      `env:"port"`
+	Hosts []string `env:"hosts"`
+	Auth  struct {
+		User string `env:"user"`
+	} `env:"auth"`
+}
+
+func Test_ReadConfig_raw_json(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Host    string          `env:"host"`
+		Plugin  RawSection      `env:"plugin"`
+		Message json.RawMessage `env:"message"`
+		Tag     json.RawMessage `env:"tag"`
+	}{}
+	data := `{
+		"host": "h",
+		"plugin": {"name": "p\"}", "port": 80, // comment }
+			"hosts": ["a", "b"], "auth": {"user": "u"}},
+		"message": [1, /* "b", */ {"a": "//x/*"}],
+		"tag": "x"
+	}`
+
+	// Act
+	err := NewConfigReader().AddString(data, FtJson, "json").Strict(true).ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "h", config.Host)
+	require.Equal(t, FtJson, config.Plugin.Format)
+	require.Equal(t, `{"name": "p\"}", "port": 80, 
+			"hosts": ["a", "b"], "auth": {"user": "u"}}`, string(config.Plugin.Data))
+	require.Equal(t, `[1,  {"a": "//x/*"}]`, string(config.Message))
+	require.True(t, json.Valid(config.Plugin.Data))
+	require.Equal(t, `"x"`, string(config.Tag))
+
+	plugin := &rawPluginConfig{}
+	require.Nil(t, config.Plugin.Decode(plugin))
+	require.Equal(t, "p\"}", plugin.Name)
+	require.Equal(t, 80, plugin.Port)
+	require.Equal(t, []string{"a", "b"}, plugin.Hosts)
+	require.Equal(t, "u", plugin.Auth.User)
+}
+
+func Test_ReadConfig_raw_ini(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Host    string          `env:"host"`
+		Plugin  RawSection      `env:"plugin"`
+		Message json.RawMessage `env:"plugin"`
+	}{}
+	data := "host = h\n[plugin]\nname = p\nport = 80\nhosts[] = a\nhosts[] = b\n[plugin.auth]\nuser = u\n"
+
+	// Act
+	err := NewConfigReader().AddString(data, FtIni, "ini").Strict(true).ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, FtIni, config.Plugin.Format)
+	require.JSONEq(t, `{"name": "p", "port": 80, "hosts": ["a", "b"], "auth": {"user": "u"}}`, string(config.Message))
+
+	plugin := &rawPluginConfig{}
+	require.Nil(t, config.Plugin.Decode(plugin))
+	require.Equal(t, "p", plugin.Name)
+	require.Equal(t, 80, plugin.Port)
+	require.Equal(t, []string{"a", "b"}, plugin.Hosts)
+	require.Equal(t, "u", plugin.Auth.User)
+}
+
+func Test_ReadConfig_raw_sources(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Plugin RawSection `env:"plugin"`
+	}{}
+	t.Setenv("plugin", `{"name": "env"}`)
+
+	// Act
+	err := NewConfigReader().
+		AddString("plugin.name = p\nplugin.port = 80", FtEnv, "env").
+		AddEnvironment().
+		ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, RawSection{Format: FtJson, Data: []byte(`{"name": "env"}`)}, config.Plugin)
+}
+
+func Test_ReadConfig_raw_missing(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Plugin   RawSection      `env:"plugin"`
+		Required json.RawMessage `env:"required,required"`
+	}{}
+
+	// Act
+	err := NewConfigReader().AddString("host = h", FtEnv, "env").ReadConfig(config)
+
+	// Assert
+	require.EqualError(t, err, "required field Required value is missing")
+	require.Equal(t, RawSection{}, config.Plugin)
+}
+
+func Test_RawSection_Decode_options(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Plugin RawSection `env:"plugin"`
+	}{}
+	data := `{"plugin": {"max_conns": 5, "Mode": "a", "mode": "b", "user_name": "u"}}`
+	plugin := &struct {
+		MaxConns int
+		Mode     string `env:"Mode"`
+		User     string `json:"user_name"`
+	}{}
+
+	// Act
+	err := NewConfigReader().
+		WithNaming(NamingSnakeCase).
+		CaseSensitive(true).
+		FallbackTags(true).
+		AddString(data, FtJson, "json").
+		ReadConfig(config)
+	require.Nil(t, err)
+	err = config.Plugin.Decode(plugin)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, 5, plugin.MaxConns)
+	require.Equal(t, "a", plugin.Mode)
+	require.Equal(t, "u", plugin.User)
+}
+
+func Test_Marshal_raw(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Host   string     `env:"host"`
+		Plugin RawSection `env:"plugin"`
+	}{Host: "h", Plugin: RawSection{Format: FtJson, Data: []byte(`{"name": "p", "port": 80}`)}}
+
+	// Act
+	b, err := Marshal(config, FtEnv)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "host = h\nplugin.name = p\nplugin.port = 80\n", string(b))
+}
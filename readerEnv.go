@@ -5,7 +5,6 @@ import (
 	"bytes"
 	"errors"
 	"io"
-	"strings"
 )
 
 func (cr *configReader) parseEnvData(r *bufio.Reader, it intermediateTree, si []structInfo, sourceId int) error {
@@ -17,19 +16,11 @@ func (cr *configReader) parseEnvData(r *bufio.Reader, it intermediateTree, si []
 			}
 			return err
 		}
-		isSlice := strings.HasSuffix(name, "[]")
-		if isSlice {
-			name = name[:len(name)-2]
-		}
-		openIndex := strings.Index(name, "[")
-		isMap := len(name) >= 4 && !isSlice && openIndex > 0 && openIndex < len(name)-2 && strings.HasSuffix(name, "]")
-		key := ""
-		if isMap {
-			key = name[openIndex+1 : len(name)-1]
-			name = name[:openIndex]
-		}
+		cr.data.keyLine = cr.data.currentLine
+		name, key, isSlice := splitCollectionName(name)
+		name = cr.sourceKeyName(name)
 
-		found, foundInfo, continue_, err := cr.findFieldByName(r, si, name, true)
+		found, foundInfo, continue_, err := cr.findField(r, it, si, name, key, isSlice, true, sourceId)
 		if err != nil {
 			if err == io.EOF {
 				break
@@ -46,7 +37,9 @@ func (cr *configReader) parseEnvData(r *bufio.Reader, it intermediateTree, si []
 			return err
 		}
 
-		addValue(foundInfo, it, name, value, key, isSlice, sourceId)
+		if errAdd := cr.addValue(foundInfo, it, name, value, key, isSlice, sourceId); errAdd != nil {
+			return errAdd
+		}
 		if err == io.EOF {
 			break
 		}
@@ -111,7 +104,7 @@ func (cr *configReader) readEnvName(r *bufio.Reader) (string, error) {
 	if started && name == "" {
 		return "", errors.New("wrong format: can't read name")
 	}
-	name = strings.ToLower(name)
+	name = cr.keyCase(name)
 
 	return name, err
 }
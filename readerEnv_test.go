@@ -234,3 +234,46 @@ func test_parseEnvData_wrongValue(t *testing.T, testCase testCaseEnvWrongValue)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), testCase.check)
 }
+
+type testCaseEnvInlineMap struct {
+	data  string
+	value map[string]string
+	err   string
+}
+
+func Test_parseEnvData_inlineMap_cases(t *testing.T) {
+	// Arrange
+	cases := []testCaseEnvInlineMap{
+		{"env_1=a:1,b:2", map[string]string{"a": "1", "b": "2"}, ""},
+		{"env_1=' a : 1 , B:x:y '", map[string]string{"a": "1", "B": "x:y"}, ""},
+		{"env_1=a:1\nenv_1[b]=2", map[string]string{"a": "1", "b": "2"}, ""},
+		{"env_1=", map[string]string{}, ""},
+		{"env_1=a:1,b", nil, "invalid map value for key \"env_1\" (1:11)"},
+	}
+
+	// Act & Assert
+	for _, c := range cases {
+		t.Log("Test case:", c.data)
+		test_parseEnvData_inlineMap(t, c)
+	}
+}
+
+func test_parseEnvData_inlineMap(t *testing.T, testCase testCaseEnvInlineMap) {
+	// Arrange
+	r := bufio.NewReader(strings.NewReader(testCase.data))
+	it := make(intermediateTree)
+	si := []structInfo{{keyName: "env_1", isMap: true, separator: sepDefault, separator2: sep2Default}}
+	cr := &configReader{options: ConfigOptions{RewriteValues: true}}
+	cr.data.currentLine = 1
+
+	// Act
+	err := cr.parseEnvData(r, it, si, 0)
+
+	// Assert
+	if testCase.err != "" {
+		require.EqualError(t, err, testCase.err)
+		return
+	}
+	require.Nil(t, err)
+	require.Equal(t, testCase.value, it["env_1"][0].value)
+}
@@ -4,14 +4,40 @@ import (
 	"os"
 )
 
-func (cr *configReader) readEnvironment(it intermediateTree, si []structInfo, sourceId int) {
+func (cr *configReader) readEnvironment(it intermediateTree, si []structInfo, sourceId int) error {
+	cr.data.keyLine = 0
 	for _, s := range si {
-		if val, ok := os.LookupEnv(s.keyName); ok {
-			if _, ok := it[s.keyName]; !ok {
-				it[s.keyName] = []intermediateData{{source: sourceId, value: val, valueType: vtAny}}
-			} else {
-				it[s.keyName] = append(it[s.keyName], intermediateData{source: sourceId, value: val, valueType: vtAny})
+		for _, name := range append([]string{s.keyName}, s.aliases...) {
+			if err := cr.readEnvironmentValue(it, s, name, sourceId); err != nil {
+				return err
 			}
 		}
 	}
+	return nil
+}
+
+// Read the environment variable of the key
+// name - key name of the field or its alias
+func (cr *configReader) readEnvironmentValue(it intermediateTree, s structInfo, name string, sourceId int) error {
+	variable, ok := cr.sourceVariableName(name)
+	if !ok {
+		return nil
+	}
+	if val, ok := os.LookupEnv(variable); ok {
+		if s.isRaw {
+			cr.addRawValue(s, it, name, val, "", false, sourceId)
+		} else if s.isSlice || s.isMap {
+			if err := cr.addValue(s, it, name, val, "", false, sourceId); err != nil {
+				return err
+			}
+		} else if _, ok := it[name]; !ok {
+			it[name] = []intermediateData{{source: sourceId, value: val, valueType: vtAny}}
+		} else {
+			it[name] = append(it[name], intermediateData{source: sourceId, value: val, valueType: vtAny})
+		}
+	}
+	if s.isSlice && !cr.data.schemaless {
+		return cr.readEnvironmentIndexes(it, s, name, variable, sourceId)
+	}
+	return nil
 }
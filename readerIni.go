@@ -43,20 +43,11 @@ func (cr *configReader) parseIniData(r *bufio.Reader, it intermediateTree, si []
 			continue
 		}
 
-		name := prefix + str
-		isSlice := strings.HasSuffix(name, "[]")
-		if isSlice {
-			name = name[:len(name)-2]
-		}
-		openIndex := strings.Index(name, "[")
-		isMap := len(name) >= 4 && !isSlice && openIndex > 0 && openIndex < len(name)-2 && strings.HasSuffix(name, "]")
-		key := ""
-		if isMap {
-			key = name[openIndex+1 : len(name)-1]
-			name = name[:openIndex]
-		}
+		name, key, isSlice := splitCollectionName(prefix + str)
+		name = cr.sourceKeyName(name)
+		cr.data.keyLine = cr.data.currentLine
 
-		found, foundInfo, continue_, err := cr.findFieldByName(r, si, name, false)
+		found, foundInfo, continue_, err := cr.findField(r, it, si, name, key, isSlice, false, sourceId)
 		if err != nil {
 			if err == io.EOF {
 				break
@@ -73,7 +64,9 @@ func (cr *configReader) parseIniData(r *bufio.Reader, it intermediateTree, si []
 			return err
 		}
 
-		addValue(foundInfo, it, name, value, key, isSlice, sourceId)
+		if errAdd := cr.addValue(foundInfo, it, name, value, key, isSlice, sourceId); errAdd != nil {
+			return errAdd
+		}
 		if err == io.EOF {
 			break
 		}
@@ -162,7 +155,7 @@ func (cr *configReader) readIniNameOrSection(r *bufio.Reader) (string, bool, err
 		return "", false, errors.New("wrong format: can't read name")
 	}
 	str = strings.Trim(str, " \t")
-	str = strings.ToLower(str)
+	str = cr.keyCase(str)
 
 	return str, isName, err
 }
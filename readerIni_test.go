@@ -253,3 +253,46 @@ func test_parseIniData_wrongValue(t *testing.T, testCase testCaseIniWrongValue)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), testCase.check)
 }
+
+type testCaseIniInlineMap struct {
+	data  string
+	value map[string]string
+	err   string
+}
+
+func Test_parseIniData_inlineMap_cases(t *testing.T) {
+	// Arrange
+	cases := []testCaseIniInlineMap{
+		{"[sub]\nenv_1=a:1,b:2", map[string]string{"a": "1", "b": "2"}, ""},
+		{"[sub]\nenv_1=' a : 1 , B:x:y '", map[string]string{"a": "1", "B": "x:y"}, ""},
+		{"[sub]\nenv_1=a:1\nenv_1[b]=2", map[string]string{"a": "1", "b": "2"}, ""},
+		{"[sub]\nenv_1=", map[string]string{}, ""},
+		{"[sub]\nenv_1=a:1,b", nil, "invalid map value for key \"sub.env_1\" (2:11)"},
+	}
+
+	// Act & Assert
+	for _, c := range cases {
+		t.Log("Test case:", c.data)
+		test_parseIniData_inlineMap(t, c)
+	}
+}
+
+func test_parseIniData_inlineMap(t *testing.T, testCase testCaseIniInlineMap) {
+	// Arrange
+	r := bufio.NewReader(strings.NewReader(testCase.data))
+	it := make(intermediateTree)
+	si := []structInfo{{keyName: "sub.env_1", isMap: true, separator: sepDefault, separator2: sep2Default}}
+	cr := &configReader{options: ConfigOptions{RewriteValues: true}}
+	cr.data.currentLine = 1
+
+	// Act
+	err := cr.parseIniData(r, it, si, 0)
+
+	// Assert
+	if testCase.err != "" {
+		require.EqualError(t, err, testCase.err)
+		return
+	}
+	require.Nil(t, err)
+	require.Equal(t, testCase.value, it["sub.env_1"][0].value)
+}
@@ -30,6 +30,7 @@ func (cr *configReader) parseJsonData(r *bufio.Reader, it intermediateTree, si [
 	notComma := false
 	found := false
 	foundInfo := structInfo{}
+	index := ""
 	var err error = nil
 
 	if !data.isObject {
@@ -43,6 +44,7 @@ func (cr *configReader) parseJsonData(r *bufio.Reader, it intermediateTree, si [
 			if err != nil {
 				return cr.processEofError(err)
 			}
+			cr.data.keyLine = cr.data.currentLine
 
 			isDuplicate := cr.checkDuplicates(data.prefix+name, it, sourceId)
 			if isDuplicate {
@@ -51,7 +53,7 @@ func (cr *configReader) parseJsonData(r *bufio.Reader, it intermediateTree, si [
 
 			doReturn, continue_, err := cr.setJsonTempDataFromName(&data, name)
 			if doReturn {
-				if data.prefix == "" {
+				if data.isRoot && err == nil {
 					return cr.readJsonTillEnd(r)
 				}
 				return cr.processEofError(err)
@@ -59,11 +61,29 @@ func (cr *configReader) parseJsonData(r *bufio.Reader, it intermediateTree, si [
 				continue
 			}
 
+			index = ""
 			if data.foundInfo.keyName != "" && data.foundInfo.isMap {
 				found = true
 				foundInfo = data.foundInfo
 			} else {
 				found, foundInfo = cr.findFieldByJsonName(si, data.prefix+name)
+				if base, key, ok := splitIndexName(name); !found && ok {
+					found, foundInfo = findStructInfo(si, data.prefix+base)
+					found = found && foundInfo.isSlice
+					index = key
+				}
+				if !found {
+					found, foundInfo = findRemainInfo(si, data.prefix+name)
+				}
+				if cr.data.schemaless {
+					found = true
+					foundInfo, err = cr.getSchemalessInfo(it, data.prefix+name, false, false, sourceId)
+					if err != nil {
+						return err
+					}
+				} else if !found && !cr.isStrippedKey(data.prefix+name) {
+					cr.addUnknownKey(data.prefix + name)
+				}
 			}
 
 			data.parseState = psJsonValue
@@ -71,6 +91,9 @@ func (cr *configReader) parseJsonData(r *bufio.Reader, it intermediateTree, si [
 		case psJsonValue:
 			valueResult := cr.readJsonValue(r)
 			if valueResult.err != nil {
+				if (foundInfo.isSecret || data.foundInfo.isSecret) && valueResult.value != "" {
+					return errors.New("wrong value format: " + cr.redact(valueResult.value) + " " + cr.currentPointInfo())
+				}
 				return cr.processEofError(valueResult.err)
 			} else if valueResult.divider == ',' {
 				if notComma && valueResult.value == "" && !valueResult.isString {
@@ -80,7 +103,18 @@ func (cr *configReader) parseJsonData(r *bufio.Reader, it intermediateTree, si [
 			} else if valueResult.divider == '}' && !data.isObject || valueResult.divider == ']' && data.isObject {
 				return cr.invalidCharacterError()
 			}
-			if valueResult.isOpener {
+			if valueResult.isOpener && index != "" && found {
+				return errors.New("slice item \"" + data.prefix + name + "\" must be a single value " + cr.currentPointInfo())
+			} else if valueResult.isOpener && found && foundInfo.isRaw && foundInfo.keyName == data.prefix+name {
+				text, err := cr.readJsonRaw(r, valueResult.value)
+				if err != nil {
+					return err
+				}
+				cr.addRawValue(foundInfo, it, foundInfo.keyName, text, "", false, sourceId)
+				divider = ' '
+				data.parseState = psJsonDivider
+				continue
+			} else if valueResult.isOpener {
 				newData, doReturn, continue_, break_, err := cr.setJsonTempDataFromValue(data, valueResult.value, name)
 				if doReturn {
 					return cr.processEofError(err)
@@ -101,8 +135,8 @@ func (cr *configReader) parseJsonData(r *bufio.Reader, it intermediateTree, si [
 			}
 			divider = valueResult.divider
 
-			foundInfo = data.foundInfo
-			if foundInfo.keyName != "" {
+			if data.foundInfo.keyName != "" && (data.foundInfo.isSlice || data.foundInfo.isMap || !found) {
+				foundInfo = data.foundInfo
 				found = true
 				if foundInfo.isSlice || foundInfo.isMap {
 					data.prefix = strings.TrimSuffix(data.prefix, ".")
@@ -118,7 +152,12 @@ func (cr *configReader) parseJsonData(r *bufio.Reader, it intermediateTree, si [
 						valueResult.value = nilDefault
 					}
 				}
-				if foundInfo.isMap {
+				if index != "" {
+					err = cr.addJsonValue(foundInfo, it, foundInfo.keyName, valueResult.value, index, vType, sourceId)
+				} else if foundInfo.isMap && foundInfo.keyName == data.prefix+name && !cr.data.schemaless {
+					// value of the map itself, e.g. null or "*unset"
+					err = cr.addJsonValue(foundInfo, it, foundInfo.keyName, valueResult.value, "", vType, sourceId)
+				} else if foundInfo.isMap {
 					err = cr.addJsonValue(foundInfo, it, data.prefix, valueResult.value, name, vType, sourceId)
 				} else if !(vType == vtEmpty && !valueResult.isString && valueResult.divider != ' ') {
 					err = cr.addJsonValue(foundInfo, it, data.prefix+name, valueResult.value, "", vType, sourceId)
@@ -254,7 +293,7 @@ func (cr *configReader) readJsonName(r *bufio.Reader) (string, error) {
 	if name == "" && (err == nil || err == io.EOF) {
 		err = errors.New("wrong format: can't read name")
 	} else {
-		name = strings.ToLower(name)
+		name = cr.keyCase(name)
 	}
 
 	return name, err
@@ -319,7 +358,7 @@ func (cr *configReader) readJsonValue(r *bufio.Reader) jsonReadValueResult {
 				} else {
 					return jsonReadValueResult{"", false, false, ' ', cr.invalidCharacterError()}
 				}
-			} else if !isQuoted && containsRune([]rune{',', '}', ']', ' ', '\t'}, rn) {
+			} else if !isQuoted && containsRune([]rune{',', '}', ']', ' ', '\t', '\r', '\n'}, rn) {
 				if rn == ',' || rn == '}' || rn == ']' {
 					divider = rn
 				}
@@ -445,7 +484,7 @@ func (cr *configReader) getJsonValueType(value string) valueType {
 func (cr *configReader) setJsonTempDataFromName(data *jsonTempData, name string) (bool, bool, error) {
 	if data.prefix == "ROOT" {
 		if name == "{" {
-			data.prefix = ""
+			data.prefix = cr.sourceRootPrefix()
 			data.isObject = true
 			return false, true, nil
 		} else {
@@ -467,7 +506,7 @@ func (cr *configReader) setJsonTempDataFromValue(data jsonTempData, value, name
 	newData := jsonTempData{}
 	continue_ := false
 	if value == "{" {
-		newData.prefix = cr.getJsonPrefix(data.prefix, name)
+		newData.prefix = cr.sourceJsonPrefix(cr.getJsonPrefix(data.prefix, name))
 		newData.isObject = true
 		continue_ = true
 	} else if value == "[" {
@@ -129,6 +129,11 @@ func Test_parseJsonData_success_cases(t *testing.T) {
 		{"{\"env_1\":[1,\"*nil\",2]}", "env_1", "1,*nil,2", vtNumber, true, false, ""},
 		{"{\"env_1\":[\"*nil\",1,\"*nil\"]}", "env_1", "*nil,1,*nil", vtNumber, true, false, ""},
 		{"{\"env_1\":[\"*nil\",1,null]}", "env_1", "*nil,1,*nil", vtNumber, true, false, ""},
+		{"{\n\"env_1\": 123\n}\n", "env_1", "123", vtNumber, false, false, ""},
+		{"{\"env_1\": [1,\n2\n]}", "env_1", "1,2", vtNumber, true, false, ""},
+		{"{\r\n\"env_1\": 123\r\n}\r\n", "env_1", "123", vtNumber, false, false, ""},
+		{"{\"env_1\": true\r\n}", "env_1", "true", vtBool, false, false, ""},
+		{"{\"env_1\": [1,\r\n2\r\n]}", "env_1", "1,2", vtNumber, true, false, ""},
 	}
 
 	// Act & Assert
@@ -298,3 +303,72 @@ func test_parseJsonData_duplicateKey(t *testing.T, testCase testCaseJsonDuplicat
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "duplicate key: "+testCase.keyName)
 }
+
+type testCaseNullCollections struct {
+	data   string
+	ft     formatType
+	ports  []int
+	labels map[string]string
+	names  []string
+}
+
+func Test_ReadConfig_null_collections_cases(t *testing.T) {
+	// Arrange
+	cases := []testCaseNullCollections{
+		{`{"ports": null}`, FtJson, nil, nil, nil},
+		{`{"labels": null}`, FtJson, nil, nil, nil},
+		{`{"ports": [1], "labels": null}`, FtJson, []int{1}, nil, nil},
+		{`{"labels": {"a": "1"}, "ports": null}`, FtJson, nil, map[string]string{"a": "1"}, nil},
+		{`{"labels": null, "db": {"names": ["n"]}}`, FtJson, nil, nil, []string{"n"}},
+		{`{"db": {"names[1]": "n"}}`, FtJson, nil, nil, []string{"", "n"}},
+		{"ports = *nil", FtEnv, nil, nil, nil},
+		{"labels = a:1\nlabels = *nil", FtEnv, nil, nil, nil},
+		{"labels = *nil", FtIni, nil, nil, nil},
+		{"ports = *nil\n[db]\nnames = *nil", FtIni, nil, nil, nil},
+	}
+
+	// Act & Assert
+	for _, c := range cases {
+		t.Log("Test case:", c.data)
+		test_ReadConfig_null_collections(t, c)
+	}
+}
+
+func test_ReadConfig_null_collections(t *testing.T, testCase testCaseNullCollections) {
+	// Arrange
+	config := &struct {
+		Ports  []int             `env:"ports"`
+		Labels map[string]string `env:"labels"`
+		Db     struct {
+			Names []string `env:"names"`
+		} `env:"db"`
+	}{}
+
+	// Act
+	err := NewConfigReader().AddString(testCase.data, testCase.ft, "test").ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, testCase.ports, config.Ports)
+	require.Equal(t, testCase.labels, config.Labels)
+	require.Equal(t, testCase.names, config.Db.Names)
+}
+
+func Test_ReadConfig_null_map_default(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Labels map[string]string `env:"labels" def:"a:1"`
+		Limits map[string]int    `env:"limits" def:"a:1"`
+	}{}
+
+	// Act
+	err := NewConfigReader().
+		AddString(`{"labels": null}`, FtJson, "json").
+		AddString("limits = *nil", FtEnv, "env").
+		ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Nil(t, config.Labels)
+	require.Nil(t, config.Limits)
+}
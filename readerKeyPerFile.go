@@ -0,0 +1,81 @@
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func (cr *configReader) readKeyPerFileDir(source configSource, it intermediateTree, si []structInfo, sourceId int) error {
+	cr.data.currentLine = 0
+	cr.data.currentPos = 0
+	cr.data.keyLine = 0
+	cr.data.currentFile = source.value
+
+	err := cr.parseKeyPerFileDir(source.value, "", source.withSubdirs, it, si, sourceId)
+	if err != nil {
+		err = cr.processNamedError(err, "directory")
+	}
+	return err
+}
+
+func (cr *configReader) parseKeyPerFileDir(dir, prefix string, withSubdirs bool, it intermediateTree, si []structInfo, sourceId int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		// skip hidden files and Kubernetes "..data" symlink farm
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		stat, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if stat.IsDir() {
+			if withSubdirs {
+				err = cr.parseKeyPerFileDir(path, prefix+entry.Name()+".", withSubdirs, it, si, sourceId)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		name, key, isSlice := splitCollectionName(cr.keyCase(prefix + entry.Name()))
+		name = cr.sourceKeyName(name)
+		found, foundInfo := findStructInfo(si, name)
+		if !found {
+			found, foundInfo = findRawInfo(si, name)
+		}
+		if !found {
+			found, foundInfo = findRemainInfo(si, name)
+		}
+		if cr.data.schemaless {
+			foundInfo, err = cr.getSchemalessInfo(it, name, key != "", isSlice, sourceId)
+			if err != nil {
+				return err
+			}
+			found = true
+		}
+		if !found {
+			cr.addUnknownKey(name)
+			continue
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		err = cr.addValue(foundInfo, it, name, trimTrailingNewLine(string(b)), key, isSlice, sourceId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
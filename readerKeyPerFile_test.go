@@ -0,0 +1,130 @@
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCaseKeyPerFileConfig struct {
+	Db struct {
+		Host string `env:"host"`
+		Port int    `env:"port"`
+	} `env:"db"`
+	Hosts  []string       `env:"hosts"`
+	Limits map[string]int `env:"limits"`
+	Cache  struct {
+		Size int `env:"size"`
+	} `env:"cache"`
+}
+
+func writeKeyPerFileDir(t *testing.T) string {
+	dir := t.TempDir()
+	data := filepath.Join(dir, "..2024_01_01")
+	require.Nil(t, os.Mkdir(data, 0o755))
+	files := map[string]string{
+		"db.host":   "localhost\n",
+		"db.port":   "5432",
+		"hosts":     "a,b",
+		"hosts[]":   "c\n",
+		"limits[a]": "1",
+		"limits[b]": "2",
+	}
+	for name, content := range files {
+		require.Nil(t, os.WriteFile(filepath.Join(data, name), []byte(content), 0o644))
+		require.Nil(t, os.Symlink(filepath.Join("..data", name), filepath.Join(dir, name)))
+	}
+	require.Nil(t, os.Symlink("..2024_01_01", filepath.Join(dir, "..data")))
+	require.Nil(t, os.Mkdir(filepath.Join(dir, "cache"), 0o755))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "cache", "size"), []byte("10"), 0o644))
+	return dir
+}
+
+func Test_AddKeyPerFileDir_success(t *testing.T) {
+	// Arrange
+	dir := writeKeyPerFileDir(t)
+	config := &testCaseKeyPerFileConfig{}
+	cr := NewConfigReader().AddKeyPerFileDir(dir, true)
+
+	// Act
+	err := cr.ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "localhost", config.Db.Host)
+	require.Equal(t, 5432, config.Db.Port)
+	require.ElementsMatch(t, []string{"a", "b", "c"}, config.Hosts)
+	require.Equal(t, map[string]int{"a": 1, "b": 2}, config.Limits)
+	require.Equal(t, 10, config.Cache.Size)
+}
+
+func Test_AddKeyPerFileDir_withoutSubdirs(t *testing.T) {
+	// Arrange
+	dir := writeKeyPerFileDir(t)
+	config := &testCaseKeyPerFileConfig{}
+	cr := NewConfigReader().AddKeyPerFileDir(dir, false)
+
+	// Act
+	err := cr.ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "localhost", config.Db.Host)
+	require.Equal(t, 0, config.Cache.Size)
+}
+
+func Test_AddKeyPerFileDir_missingDir(t *testing.T) {
+	// Arrange
+	config := &testCaseKeyPerFileConfig{}
+	cr := NewConfigReader().AddKeyPerFileDir(filepath.Join(t.TempDir(), "missing"), false)
+
+	// Act
+	err := cr.ReadConfig(config)
+
+	// Assert
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "error in directory")
+}
+
+type testCaseMapKeyCase struct {
+	caseSensitive bool
+	expected      map[string]int
+}
+
+func Test_ReadConfig_map_key_case_cases(t *testing.T) {
+	// Arrange
+	cases := []testCaseMapKeyCase{
+		{false, map[string]int{"env": 1, "ini": 2, "json": 3, "kpf": 4}},
+		{true, map[string]int{"Env": 1, "Ini": 2, "Json": 3, "Kpf": 4}},
+	}
+
+	// Act & Assert
+	for _, c := range cases {
+		t.Log("Test case:", c.caseSensitive)
+		test_ReadConfig_map_key_case_cases(t, c)
+	}
+}
+
+func test_ReadConfig_map_key_case_cases(t *testing.T, testCase testCaseMapKeyCase) {
+	// Arrange
+	dir := t.TempDir()
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "limits[Kpf]"), []byte("4"), 0o644))
+	config := &struct {
+		Limits map[string]int `env:"limits" merge:"deep"`
+	}{}
+
+	// Act
+	err := NewConfigReader().
+		AddString("limits[Env] = 1", FtEnv, "env").
+		AddString("limits[Ini] = 2", FtIni, "ini").
+		AddString(`{"limits": {"Json": 3}}`, FtJson, "json").
+		AddKeyPerFileDir(dir, false).
+		CaseSensitive(testCase.caseSensitive).
+		ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, testCase.expected, config.Limits)
+}
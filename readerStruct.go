@@ -0,0 +1,97 @@
+package configuration
+
+import (
+	"errors"
+	"math"
+	"reflect"
+)
+
+const preservedSourceName = "preserved"
+
+func newStructSource(config interface{}) (configSource, error) {
+	v := reflect.ValueOf(config)
+	if config == nil || v.Kind() == reflect.Ptr && v.IsNil() {
+		return configSource{}, errors.New("struct source is nil")
+	}
+	if v.Kind() != reflect.Ptr {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		v = ptr
+	}
+	if v.Elem().Kind() != reflect.Struct {
+		return configSource{}, errors.New("struct source must be a struct, got " + v.Elem().Type().String())
+	}
+	return configSource{name: v.Elem().Type().String(), ft: ftStruct, structValue: v.Interface()}, nil
+}
+
+// Get the sources with the values of the user config struct and bound structs set before reading
+// prefix - key prefix of the user config struct
+func (cr *configReader) preservedSources(prefix string, userConfig interface{}) []configSource {
+	sources := make([]configSource, 0, len(cr.bindings)+1)
+	add := func(prefix string, config interface{}) {
+		sources = append(sources, configSource{
+			name:        preservedSourceName,
+			ft:          ftStruct,
+			structValue: config,
+			atPrefix:    normalizePrefix(prefix),
+			priority:    math.MinInt,
+		})
+	}
+	add(prefix, userConfig)
+	for _, b := range cr.bindings {
+		add(b.prefix, b.target)
+	}
+	return sources
+}
+
+// Read the fields of the struct with non-zero values
+func (cr *configReader) readStruct(source configSource, it intermediateTree, si []structInfo, sourceId int) error {
+	cr.data.currentFile = source.name
+	cr.data.keyLine = 0
+
+	fields, err := cr.getStructInfo(source.structValue, "", "")
+	if err != nil {
+		return errors.New("error in struct \"" + source.name + "\": " + err.Error())
+	}
+
+	for _, field := range fields {
+		if field.isRemain || field.field.IsZero() {
+			continue
+		}
+		name := cr.sourceKeyName(field.keyName)
+
+		if cr.data.schemaless {
+			if !field.isRaw {
+				it[name] = append(it[name], intermediateData{source: sourceId, value: structFieldValue(field), valueType: vtAny})
+			}
+			continue
+		}
+
+		found, info := findStructInfo(si, name)
+		if !found {
+			cr.addUnknownKey(name)
+			continue
+		}
+		if field.isRaw && info.isRaw {
+			data, format := rawFieldData(field)
+			it[name] = append(it[name], intermediateData{source: sourceId, value: &rawValue{format: format, text: string(data)}, valueType: vtAny})
+		} else if !field.isRaw {
+			it[name] = append(it[name], intermediateData{source: sourceId, value: structFieldValue(field), valueType: vtAny})
+		}
+	}
+	return nil
+}
+
+// Get the value of the field as it would be read from a source: string, []string or map[string]string
+func structFieldValue(info structInfo) interface{} {
+	if info.isSlice {
+		return formatSlice(info.field)
+	} else if info.isMap {
+		return formatMap(info.field)
+	}
+	return formatScalar(info.field)
+}
+
+func (cr *configReader) isStructSource(sourceId int) bool {
+	return sourceId >= 0 && sourceId < len(cr.data.lastSources) && cr.data.lastSources[sourceId].ft == ftStruct
+}
@@ -0,0 +1,162 @@
+package configuration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type structSourceConfig struct {
+	Host    string            `env:"host" def:"localhost"`
+	Port    int               `env:"port" def:"80"`
+	Timeout time.Duration     `env:"timeout"`
+	Hosts   []string          `env:"hosts" merge:"append"`
+	Labels  map[string]string `env:"labels"`
+	Ratio   *float64          `env:"ratio"`
+	Token   string            `env:"token,secretfile"`
+	Db      struct {
+		Name string `env:"name"`
+	} `env:"db"`
+}
+
+func Test_ReadConfig_struct_source(t *testing.T) {
+	// Arrange
+	ratio := 0.5
+	defaults := structSourceConfig{Port: 8080, Timeout: time.Minute, Hosts: []string{"a"},
+		Labels: map[string]string{"k": "v"}, Ratio: &ratio, Token: "secret"}
+	defaults.Db.Name = "d1"
+	site := structSourceConfig{Hosts: []string{"c"}}
+	config := &structSourceConfig{}
+
+	// Act
+	cr := NewConfigReader().
+		AddStruct(defaults).
+		AddString("port = 81\nhosts = b\ndb.name = d2", FtEnv, "env").
+		AddStruct(&site, WithLayer("site"))
+	err := cr.ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "localhost", config.Host)
+	require.Equal(t, 81, config.Port)
+	require.Equal(t, time.Minute, config.Timeout)
+	require.Equal(t, []string{"a", "b", "c"}, config.Hosts)
+	require.Equal(t, map[string]string{"k": "v"}, config.Labels)
+	require.Equal(t, 0.5, *config.Ratio)
+	require.Equal(t, "secret", config.Token)
+	require.Equal(t, "d2", config.Db.Name)
+
+	explanation, err := cr.Explain(config)
+	require.Nil(t, err)
+	require.Equal(t, "configuration.structSourceConfig, env, site: configuration.structSourceConfig", explanation[3].Source)
+}
+
+func Test_ReadConfig_preserve_values(t *testing.T) {
+	// Arrange
+	config := &structSourceConfig{Host: "h", Port: 90, Hosts: []string{"a"}}
+	config.Db.Name = "d1"
+	bound := &struct {
+		Size int `env:"size"`
+		Ttl  int `env:"ttl"`
+	}{Size: 5, Ttl: 7}
+
+	// Act
+	cr := NewConfigReader().
+		AddString("port = 91\nhosts = b\ncache.ttl = 8", FtEnv, "env").
+		Bind("cache", bound).
+		PreserveValues(true).
+		Strict(true)
+	err := cr.ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "h", config.Host)
+	require.Equal(t, 91, config.Port)
+	require.Equal(t, []string{"a", "b"}, config.Hosts)
+	require.Equal(t, "d1", config.Db.Name)
+	require.Equal(t, 5, bound.Size)
+	require.Equal(t, 8, bound.Ttl)
+	require.Len(t, cr.sources, 1)
+
+	explanation, err := cr.Explain(config)
+	require.Nil(t, err)
+	require.Equal(t, "preserved", explanation[0].Source)
+}
+
+func Test_ReadConfig_without_preserve_values(t *testing.T) {
+	// Arrange
+	config := &structSourceConfig{Host: "h", Port: 90}
+
+	// Act
+	err := NewConfigReader().AddString("hosts = b", FtEnv, "env").ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "localhost", config.Host)
+	require.Equal(t, 80, config.Port)
+}
+
+func Test_ReadTree_struct_source(t *testing.T) {
+	// Arrange
+	defaults := structSourceConfig{Port: 8080, Hosts: []string{"a", "b"}}
+	defaults.Db.Name = "d"
+
+	// Act
+	tree, err := NewConfigReader().AddStruct(defaults, AtPrefix("app")).ReadTree()
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, []string{"app.db.name", "app.hosts", "app.port"}, tree.Keys(""))
+	hosts, _ := tree.GetStringSlice("app.hosts")
+	require.Equal(t, []string{"a", "b"}, hosts)
+}
+
+type testCaseAddStructError struct {
+	config interface{}
+	err    string
+}
+
+func Test_AddStruct_error_cases(t *testing.T) {
+	// Arrange
+	var nilConfig *structSourceConfig
+	cases := []testCaseAddStructError{
+		{nil, "struct source is nil"},
+		{nilConfig, "struct source is nil"},
+		{1, "struct source must be a struct, got int"},
+	}
+
+	for _, c := range cases {
+		test_AddStruct_error_cases(t, c)
+	}
+}
+
+func test_AddStruct_error_cases(t *testing.T, testCase testCaseAddStructError) {
+	// Act
+	errs := NewConfigReader().AddStruct(testCase.config).GetErrors()
+
+	// Assert
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], testCase.err)
+}
+
+func Test_ReadConfig_struct_source_unset_markers(t *testing.T) {
+	// Arrange
+	defaults := structSourceConfig{Host: "*unset", Hosts: []string{"*unset", "*unset:a"},
+		Labels: map[string]string{"k": "*unset"}}
+	config := &structSourceConfig{Token: "*unset"}
+
+	// Act
+	err := NewConfigReader().
+		AddString("hosts = a\nlabels[k] = v", FtEnv, "env").
+		AddStruct(defaults).
+		PreserveValues(true).
+		ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "*unset", config.Host)
+	require.Equal(t, []string{"a", "*unset", "*unset:a"}, config.Hosts)
+	require.Equal(t, map[string]string{"k": "*unset"}, config.Labels)
+	require.Equal(t, "*unset", config.Token)
+}
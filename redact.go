@@ -0,0 +1,95 @@
+package configuration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"reflect"
+)
+
+// Get a copy of the user config with secret fields redacted, safe for logging
+// userConfig - pointer to the user config struct
+func Redacted[T any](userConfig *T) (*T, error) {
+	if userConfig == nil {
+		return nil, errors.New("user config is nil")
+	}
+
+	result := new(T)
+	*result = *userConfig
+
+	cr := &configReader{}
+	si, err := cr.getStructInfo(result, "", "")
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range si {
+		if info.isSecret {
+			redactValue(info.field)
+		}
+	}
+
+	return result, nil
+}
+
+// Get the value to show instead of the secret one
+func (cr *configReader) redact(value string) string {
+	if cr.options.RedactWithHash {
+		hash := sha256.Sum256([]byte(value))
+		return "sha256:" + hex.EncodeToString(hash[:])[:redactedHashLen]
+	}
+	return redactedValue
+}
+
+func (cr *configReader) redactIfSecret(info structInfo, value string) string {
+	if info.isSecret {
+		return cr.redact(value)
+	}
+	return value
+}
+
+func redactValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(redactedValue)
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		p := reflect.New(v.Type().Elem())
+		redactValue(p.Elem())
+		v.Set(p)
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+		slice := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if v.Index(i).Kind() != reflect.Ptr || !v.Index(i).IsNil() {
+				slice.Index(i).Set(reflect.New(v.Type().Elem()).Elem())
+				if v.Index(i).Kind() == reflect.Ptr {
+					slice.Index(i).Set(reflect.New(v.Type().Elem().Elem()))
+				}
+				redactValue(slice.Index(i))
+			}
+		}
+		v.Set(slice)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			redactValue(v.Index(i))
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			item := reflect.New(v.Type().Elem()).Elem()
+			redactValue(item)
+			m.SetMapIndex(iter.Key(), item)
+		}
+		v.Set(m)
+	default:
+		v.SetZero()
+	}
+}
@@ -0,0 +1,125 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCaseRedactedConfig struct {
+	User     string            `env:"user"`
+	Password string            `env:"password,secret"`
+	Token    *string           `env:"token,secret"`
+	Keys     []string          `env:"keys,secret"`
+	Pins     [2]int            `env:"pins,secret"`
+	Headers  map[string]string `env:"headers,secret"`
+	Port     int               `env:"port,secret"`
+	Db       struct {
+		Password string `env:"password,secretfile"`
+	} `env:"db"`
+}
+
+func Test_Redacted_success(t *testing.T) {
+	// Arrange
+	config := &testCaseRedactedConfig{
+		User:     "user",
+		Password: "password",
+		Token:    addr("token"),
+		Keys:     []string{"a", "b"},
+		Pins:     [2]int{1, 2},
+		Headers:  map[string]string{"x": "y"},
+		Port:     5432,
+	}
+	config.Db.Password = "password"
+
+	// Act
+	result, err := Redacted(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "user", result.User)
+	require.Equal(t, redactedValue, result.Password)
+	require.Equal(t, redactedValue, *result.Token)
+	require.Equal(t, []string{redactedValue, redactedValue}, result.Keys)
+	require.Equal(t, [2]int{0, 0}, result.Pins)
+	require.Equal(t, map[string]string{"x": redactedValue}, result.Headers)
+	require.Equal(t, 0, result.Port)
+	require.Equal(t, redactedValue, result.Db.Password)
+
+	require.Equal(t, "password", config.Password)
+	require.Equal(t, "token", *config.Token)
+	require.Equal(t, []string{"a", "b"}, config.Keys)
+	require.Equal(t, map[string]string{"x": "y"}, config.Headers)
+	require.Equal(t, "password", config.Db.Password)
+}
+
+func Test_Redacted_nil(t *testing.T) {
+	// Act
+	_, err := Redacted[testCaseRedactedConfig](nil)
+
+	// Assert
+	require.NotNil(t, err)
+}
+
+func Test_redact_hash(t *testing.T) {
+	// Arrange
+	cr := &configReader{options: ConfigOptions{RedactWithHash: true}}
+
+	// Act
+	result := cr.redact("password")
+
+	// Assert
+	require.Equal(t, "sha256:5e884898", result)
+}
+
+func Test_ReadConfig_secretNotInErrors_cases(t *testing.T) {
+	// Arrange
+	c1 := struct {
+		Pins [1]string `env:"pins,secret"`
+	}{}
+	c2 := struct {
+		Pin string `env:"pin,secret"`
+	}{}
+	cases := []testCaseReadConfigSecretError{
+		{&c1, "pins = s3cr3t,s3cr3t2", FtEnv},
+		{&c2, "{\"pin\": s3cr3t}", FtJson},
+	}
+
+	// Act & Assert
+	for i, c := range cases {
+		t.Log("Test case:", i)
+		test_ReadConfig_secretNotInErrors(t, c)
+	}
+}
+
+type testCaseReadConfigSecretError struct {
+	config interface{}
+	data   string
+	ft     formatType
+}
+
+func test_ReadConfig_secretNotInErrors(t *testing.T, testCase testCaseReadConfigSecretError) {
+	// Arrange
+	cr := NewConfigReader().AddString(testCase.data, testCase.ft, "test")
+
+	// Act
+	err := cr.ReadConfig(testCase.config)
+
+	// Assert
+	require.NotNil(t, err)
+	require.NotContains(t, err.Error(), "s3cr3t")
+	require.Contains(t, err.Error(), redactedValue)
+}
+
+func Test_ReadConfig_secretNotInErrors_substring(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Pin string `env:"pin,secret"`
+	}{}
+
+	// Act
+	err := NewConfigReader().AddString(`{"pin": w}`, FtJson, "test").ReadConfig(config)
+
+	// Assert
+	require.EqualError(t, err, "error in string \"test\": wrong value format: ****** (1:10)")
+}
@@ -0,0 +1,85 @@
+package configuration
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Find the field with "remain" option for the unclaimed key
+// the field with the longest prefix wins
+func findRemainInfo(si []structInfo, name string) (bool, structInfo) {
+	found := false
+	foundInfo := structInfo{}
+	for _, s := range si {
+		if !s.isRemain {
+			continue
+		}
+		prefix := strings.TrimSuffix(s.keyName, remainKey)
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) &&
+			(!found || len(prefix) > len(foundInfo.keyName)-len(remainKey)) {
+			found = true
+			foundInfo = s
+		}
+	}
+	return found, foundInfo
+}
+
+func (cr *configReader) addRemainValue(info structInfo, it intermediateTree, name, value string, vType valueType, isSlice bool, sourceId int) {
+	name = strings.TrimPrefix(name, strings.TrimSuffix(info.keyName, remainKey))
+
+	var items map[string]remainItem
+	for _, d := range it[info.keyName] {
+		if d.source == sourceId {
+			items = d.value.(map[string]remainItem)
+		}
+	}
+	if items == nil {
+		items = map[string]remainItem{}
+		it[info.keyName] = append(it[info.keyName], intermediateData{source: sourceId, value: items, valueType: vtAny, line: cr.data.keyLine})
+	}
+
+	item := items[name]
+	if isSlice && item.isSlice {
+		item.values = append(item.values, value)
+	} else {
+		item.values = []string{value}
+	}
+	item.isSlice = isSlice
+	if vType != vtNull || item.valueType == vtEmpty {
+		item.valueType = vType
+	}
+	items[name] = item
+}
+
+// Set all the unclaimed keys from all the sources, later sources override the keys of earlier ones
+func (cr *configReader) setRemainValue(info structInfo, data []intermediateData) {
+	items := map[string]remainItem{}
+	for _, d := range data {
+		for k, item := range d.value.(map[string]remainItem) {
+			items[k] = item
+		}
+	}
+
+	if info.fieldType.Kind() == reflect.String {
+		result := make(map[string]string, len(items))
+		for k, item := range items {
+			result[k] = strings.Join(item.values, info.separator)
+		}
+		info.field.Set(reflect.ValueOf(result))
+		return
+	}
+
+	result := make(map[string]interface{}, len(items))
+	for k, item := range items {
+		if !item.isSlice {
+			result[k] = typedTreeValue(item.values[0], item.valueType)
+			continue
+		}
+		values := make([]interface{}, 0, len(item.values))
+		for _, v := range item.values {
+			values = append(values, typedTreeValue(v, item.valueType))
+		}
+		result[k] = values
+	}
+	info.field.Set(reflect.ValueOf(result))
+}
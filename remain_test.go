@@ -0,0 +1,108 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReadConfig_remain_success(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Host  string            `env:"host"`
+		Extra map[string]string `env:",remain"`
+		Db    struct {
+			User  string                 `env:"user"`
+			Other map[string]interface{} `env:"other,remain"`
+		} `env:"db"`
+	}{}
+	cr := NewConfigReader().
+		AddString("host = h\nlabel = a\nlist[] = x\nlist[] = y\nlimits[k] = 1\n[db]\nuser = u\nport = 1\n", FtIni, "ini").
+		AddString(`{"label": "b", "db": {"port": 5432, "on": true, "n": null, "tags": {"a": "x"}, "ids": [1, null]}}`, FtJson, "json").
+		Strict(true)
+
+	// Act
+	err := cr.ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "h", config.Host)
+	require.Equal(t, map[string]string{"label": "b", "list": "x,y", "limits.k": "1"}, config.Extra)
+	require.Equal(t, "u", config.Db.User)
+	require.Equal(t, map[string]interface{}{
+		"port":   float64(5432),
+		"on":     true,
+		"n":      nil,
+		"tags.a": "x",
+		"ids":    []interface{}{float64(1), nil},
+	}, config.Db.Other)
+}
+
+func Test_ReadConfig_remain_empty(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Host  string            `env:"host"`
+		Extra map[string]string `env:",remain"`
+	}{}
+
+	// Act
+	err := NewConfigReader().AddString("host = h", FtEnv, "env").ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{}, config.Extra)
+}
+
+func Test_getStructInfo_remain_error(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Extra map[string]int `env:",remain"`
+	}{}
+	cr := &configReader{}
+
+	// Act
+	_, err := cr.getStructInfo(config, "", "")
+
+	// Assert
+	require.EqualError(t, err, "field Extra with remain option must be map[string]string or map[string]interface{}")
+}
+
+func Test_Marshal_remain_roundTrip(t *testing.T) {
+	// Arrange
+	type remainConfig struct {
+		Host  string                 `env:"host"`
+		Extra map[string]interface{} `env:",remain"`
+	}
+	config := &remainConfig{Host: "h", Extra: map[string]interface{}{
+		"a.b": "x", "n": nil, "num": float64(1), "ids": []interface{}{float64(1), float64(2)},
+	}}
+	result := &remainConfig{}
+
+	// Act
+	data, err := Marshal(config, FtJson)
+	require.Nil(t, err)
+	err = NewConfigReader().AddString(string(data), FtJson, "json").ReadConfig(result)
+
+	// Assert
+	require.Nil(t, err, string(data))
+	require.Equal(t, config, result, string(data))
+}
+
+func Test_JSONSchema_remain(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Host string `env:"host"`
+		Db   struct {
+			User  string            `env:"user"`
+			Extra map[string]string `env:",remain"`
+		} `env:"db"`
+	}{}
+
+	// Act
+	data, err := JSONSchema(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Contains(t, string(data), "\"user\": {\n          \"type\": \"string\"\n        }\n      },\n      \"additionalProperties\": true")
+	require.NotContains(t, string(data), remainKey)
+}
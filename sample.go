@@ -0,0 +1,85 @@
+package configuration
+
+import (
+	"errors"
+	"strings"
+)
+
+// Generate a sample configuration with all the keys of the user config struct
+// values are taken from the "def" tag, "desc" tag and required fields are written as comments
+// userConfig - pointer to the user config struct
+// formatType - format of the sample
+func GenerateSample(userConfig interface{}, formatType formatType) ([]byte, error) {
+	cr := &configReader{}
+	si, err := cr.getStructInfo(userConfig, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := cr.getSampleItems(si)
+	if err != nil {
+		return nil, err
+	}
+	return marshalItems(items, formatType)
+}
+
+func (cr *configReader) getSampleItems(si []structInfo) ([]marshalItem, error) {
+	items := make([]marshalItem, 0, len(si))
+	for _, info := range si {
+		if info.isRemain || info.isRaw {
+			continue
+		}
+		item := marshalItem{
+			key:        info.keyName,
+			valueType:  getFieldValueType(info),
+			separator:  info.separator,
+			separator2: info.separator2,
+			isMap:      info.isMap,
+			isNullable: info.isPointer,
+			isSample:   true,
+		}
+
+		if info.description != "" {
+			item.comments = append(item.comments, strings.Split(info.description, "\n")...)
+		}
+		if info.isRequired {
+			item.comments = append(item.comments, "required")
+		}
+		if info.isSecret {
+			item.comments = append(item.comments, "secret")
+		}
+
+		def := info.defValue
+		if def == nilDefault || info.isSecret {
+			def = ""
+		} else if strings.Contains(def, expandOpener) {
+			item.comments = append(item.comments, "default: "+def)
+			def = ""
+		}
+
+		if info.isSlice {
+			values := []string{}
+			if def != "" {
+				values = strings.Split(def, info.separator)
+			}
+			item.value = values
+		} else if info.isMap {
+			values := map[string]string{}
+			if def != "" {
+				for _, s := range strings.Split(def, info.separator) {
+					kv := strings.Split(s, info.separator2)
+					if len(kv) != 2 {
+						return nil, errors.New("invalid default value for field " + info.fieldName)
+					}
+					values[kv[0]] = kv[1]
+				}
+			}
+			item.value = values
+		} else {
+			item.value = def
+		}
+
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,98 @@
+package configuration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCaseSampleConfig struct {
+	Host     string         `env:"host" def:"localhost" desc:"Server host"`
+	Port     int            `env:"port,required" def:"8080"`
+	Url      string         `env:"url" def:"http://${host}"`
+	Password string         `env:"password,secret" def:"p@ss"`
+	Hosts    []string       `env:"hosts" def:"a,b" desc:"Backup hosts\nused in order"`
+	Limits   map[string]int `env:"limits" def:"x:1"`
+	Ptr      *int           `env:"ptr"`
+	Db       struct {
+		Timeout time.Duration `env:"timeout" def:"5s"`
+	} `env:"db"`
+}
+
+func Test_GenerateSample_env(t *testing.T) {
+	// Arrange
+	config := &testCaseSampleConfig{}
+
+	// Act
+	data, err := GenerateSample(config, FtEnv)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "# Server host\nhost = localhost\n"+
+		"# required\nport = 8080\n"+
+		"# default: http://${host}\nurl =\n"+
+		"# secret\npassword =\n"+
+		"# Backup hosts\n# used in order\nhosts = a,b\n# hosts[] = value\n"+
+		"limits = x:1\n# limits[key] = value\n"+
+		"ptr =\n"+
+		"db.timeout = 5s\n", string(data))
+}
+
+func Test_GenerateSample_ini(t *testing.T) {
+	// Arrange
+	config := &testCaseSampleConfig{}
+
+	// Act
+	data, err := GenerateSample(config, FtIni)
+
+	// Assert
+	require.Nil(t, err)
+	require.Contains(t, string(data), "; required\nport = 8080\n")
+	require.Contains(t, string(data), "\n[db]\ntimeout = 5s\n")
+}
+
+func Test_GenerateSample_json(t *testing.T) {
+	// Arrange
+	config := &testCaseSampleConfig{}
+
+	// Act
+	data, err := GenerateSample(config, FtJson)
+
+	// Assert
+	require.Nil(t, err)
+	require.Contains(t, string(data), "  // required\n  \"port\": 8080,\n")
+	require.Contains(t, string(data), "  \"hosts\": [\"a\", \"b\"],\n")
+	require.Contains(t, string(data), "  \"ptr\": null,\n")
+}
+
+func Test_GenerateSample_readBack_cases(t *testing.T) {
+	// Arrange
+	cases := []formatType{FtEnv, FtIni, FtJson}
+
+	// Act & Assert
+	for _, c := range cases {
+		t.Log("Test case:", c)
+		test_GenerateSample_readBack(t, c)
+	}
+}
+
+func test_GenerateSample_readBack(t *testing.T, ft formatType) {
+	// Arrange
+	data, err := GenerateSample(&testCaseSampleConfig{}, ft)
+	require.Nil(t, err)
+	config := &testCaseSampleConfig{}
+
+	// Act
+	err = NewConfigReader().AddString(string(data), ft, "sample").ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err, string(data))
+	require.Equal(t, "localhost", config.Host)
+	require.Equal(t, 8080, config.Port)
+	require.Equal(t, "http://localhost", config.Url)
+	require.Equal(t, "p@ss", config.Password)
+	require.Equal(t, []string{"a", "b"}, config.Hosts)
+	require.Equal(t, map[string]int{"x": 1}, config.Limits)
+	require.Equal(t, 5*time.Second, config.Db.Timeout)
+}
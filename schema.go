@@ -0,0 +1,268 @@
+package configuration
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Ordered json object, keeps the order of the struct fields
+type schemaObject []schemaProperty
+type schemaProperty struct {
+	name  string
+	value interface{}
+}
+
+func (obj schemaObject) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString("{")
+	for i, p := range obj {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+		name, err := json.Marshal(p.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(p.value)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(name)
+		buffer.WriteString(":")
+		buffer.Write(value)
+	}
+	buffer.WriteString("}")
+	return buffer.Bytes(), nil
+}
+
+type schemaNode struct {
+	keys     []string
+	children map[string]*schemaNode
+	items    map[string]structInfo
+	open     bool // has a field with "remain" option
+}
+
+func newSchemaNode() *schemaNode {
+	return &schemaNode{children: map[string]*schemaNode{}, items: map[string]structInfo{}}
+}
+
+// Generate JSON Schema (draft 2020-12) for the user config struct
+// userConfig - pointer to the user config struct
+func JSONSchema(userConfig interface{}) ([]byte, error) {
+	cr := &configReader{}
+	si, err := cr.getStructInfo(userConfig, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	root := newSchemaNode()
+	for _, info := range si {
+		node := root
+		path := strings.Split(info.keyName, ".")
+		if info.isRemain {
+			path[len(path)-1] = ""
+		}
+		for _, name := range path[:len(path)-1] {
+			child, ok := node.children[name]
+			if !ok {
+				if _, ok := node.items[name]; ok {
+					return nil, errors.New("key " + info.keyName + " conflicts with value " + name)
+				}
+				child = newSchemaNode()
+				node.children[name] = child
+				node.keys = append(node.keys, name)
+			}
+			node = child
+		}
+		name := path[len(path)-1]
+		if info.isRemain {
+			node.open = true
+			continue
+		}
+		if _, ok := node.children[name]; ok {
+			return nil, errors.New("value " + info.keyName + " conflicts with section")
+		}
+		if _, ok := node.items[name]; ok {
+			return nil, errors.New("duplicate key " + info.keyName)
+		}
+		node.keys = append(node.keys, name)
+		node.items[name] = info
+	}
+
+	schema := schemaObject{{"$schema", schemaDraft}}
+	schema = append(schema, root.schema()...)
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func (node *schemaNode) schema() schemaObject {
+	properties := schemaObject{}
+	required := []string{}
+	for _, name := range node.keys {
+		if child, ok := node.children[name]; ok {
+			properties = append(properties, schemaProperty{name, child.schema()})
+			continue
+		}
+		info := node.items[name]
+		properties = append(properties, schemaProperty{name, fieldSchema(info)})
+		// a default value satisfies the required option
+		if info.isRequired && (info.defValue == "" || info.defValue == nilDefault) {
+			required = append(required, name)
+		}
+	}
+
+	schema := schemaObject{{"type", "object"}}
+	if len(properties) > 0 {
+		schema = append(schema, schemaProperty{"properties", properties})
+	}
+	if len(required) > 0 {
+		schema = append(schema, schemaProperty{"required", required})
+	}
+	return append(schema, schemaProperty{"additionalProperties", node.open})
+}
+
+func fieldSchema(info structInfo) schemaObject {
+	item := valueSchema(info)
+	if info.isPointer && !info.isSlice {
+		item = nullableSchema(item)
+	}
+
+	schema := item
+	if info.isSlice {
+		if info.isPointer {
+			item = nullableSchema(item)
+		}
+		schema = schemaObject{{"type", "array"}, {"items", item}}
+		if info.size > 0 {
+			schema = append(schema, schemaProperty{"maxItems", info.size})
+		} else {
+			schema = nullableSchema(schema)
+		}
+	} else if info.isMap {
+		schema = schemaObject{{"type", []string{"object", "null"}}, {"additionalProperties", item}}
+	}
+
+	if info.description != "" {
+		schema = append(schema, schemaProperty{"description", info.description})
+	}
+	if def, ok := defaultSchemaValue(info); ok {
+		schema = append(schema, schemaProperty{"default", def})
+	}
+	return schema
+}
+
+func valueSchema(info structInfo) schemaObject {
+	if info.useParser || info.isRaw {
+		return schemaObject{}
+	}
+	switch info.fieldType.Kind() {
+	case reflect.Int8:
+		return integerSchema(math.MinInt8, math.MaxInt8)
+	case reflect.Int16:
+		return integerSchema(math.MinInt16, math.MaxInt16)
+	case reflect.Int32:
+		return integerSchema(math.MinInt32, math.MaxInt32)
+	case reflect.Int, reflect.Int64:
+		if info.fieldType.String() == "time.Duration" {
+			return schemaObject{{"type", "string"}}
+		}
+		return schemaObject{{"type", "integer"}}
+	case reflect.Uint8:
+		return integerSchema(0, math.MaxUint8)
+	case reflect.Uint16:
+		return integerSchema(0, math.MaxUint16)
+	case reflect.Uint32:
+		return integerSchema(0, math.MaxUint32)
+	case reflect.Uint, reflect.Uint64:
+		return schemaObject{{"type", "integer"}, {"minimum", 0}}
+	case reflect.Float32, reflect.Float64:
+		return schemaObject{{"type", "number"}}
+	case reflect.Bool:
+		return schemaObject{{"type", "boolean"}}
+	case reflect.Struct:
+		if info.fieldType.String() == "time.Time" {
+			return schemaObject{{"type", "string"}, {"format", "date-time"}}
+		}
+	}
+	return schemaObject{{"type", "string"}}
+}
+
+func integerSchema(min, max int64) schemaObject {
+	return schemaObject{{"type", "integer"}, {"minimum", min}, {"maximum", max}}
+}
+
+func nullableSchema(schema schemaObject) schemaObject {
+	if len(schema) == 0 || schema[0].name != "type" {
+		return schema
+	}
+	result := append(schemaObject{}, schema...)
+	result[0].value = []string{schema[0].value.(string), "null"}
+	return result
+}
+
+// Convert the "def" tag value to the json value
+// defaults with references, secrets and built-in values are skipped
+func defaultSchemaValue(info structInfo) (interface{}, bool) {
+	def := info.defValue
+	if info.isSecret || info.useParser || def == "" ||
+		strings.Contains(def, expandOpener) || isSecretFileReference(def) {
+		return nil, false
+	}
+	if def == nilDefault {
+		return nil, info.isPointer || info.isSlice || info.isMap
+	}
+	if info.fieldType.String() == "time.Time" && def == nowTime {
+		return nil, false
+	}
+
+	valueType := getFieldValueType(info)
+	if info.isSlice {
+		values := []interface{}{}
+		for _, s := range strings.Split(def, info.separator) {
+			v, ok := typedSchemaValue(strings.Trim(s, " \t"), valueType, info.isPointer)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, v)
+		}
+		return values, true
+	} else if info.isMap {
+		values := schemaObject{}
+		for _, s := range strings.Split(def, info.separator) {
+			kv := strings.SplitN(s, info.separator2, 2)
+			if len(kv) != 2 {
+				return nil, false
+			}
+			v, ok := typedSchemaValue(strings.Trim(kv[1], " \t"), valueType, false)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, schemaProperty{strings.Trim(kv[0], " \t"), v})
+		}
+		return values, true
+	}
+	return typedSchemaValue(def, valueType, info.isPointer)
+}
+
+func typedSchemaValue(value string, valueType valueType, nullable bool) (interface{}, bool) {
+	if nullable && value == nilDefault {
+		return nil, true
+	}
+	switch valueType {
+	case vtNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return nil, false
+		}
+		return json.Number(value), true
+	case vtBool:
+		b, err := strconv.ParseBool(value)
+		return b, err == nil
+	}
+	return value, true
+}
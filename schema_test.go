@@ -0,0 +1,82 @@
+package configuration
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_JSONSchema_success(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Host    string          `env:"host,required" desc:"Server host"`
+		Port    uint16          `env:"port,required" def:"8080"`
+		Ratio   *float64        `env:"ratio"`
+		Token   string          `env:"token,secret" def:"t0ken"`
+		Url     string          `env:"url" def:"http://${host}"`
+		Array   [3]int          `env:"array" def:"1,2"`
+		Hosts   []string        `env:"hosts" def:"a;b" sep:";"`
+		Limits  map[string]bool `env:"limits" def:"a:true"`
+		Timeout time.Duration   `env:"timeout" def:"5s"`
+		Db      struct {
+			Start time.Time `env:"start" def:"now"`
+		} `env:"db"`
+	}{}
+
+	// Act
+	data, err := JSONSchema(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"host": {"type": "string", "description": "Server host"},
+			"port": {"type": "integer", "minimum": 0, "maximum": 65535, "default": 8080},
+			"ratio": {"type": ["number", "null"], "default": null},
+			"token": {"type": "string"},
+			"url": {"type": "string"},
+			"array": {"type": "array", "items": {"type": "integer"}, "maxItems": 3, "default": [1, 2]},
+			"hosts": {"type": ["array", "null"], "items": {"type": "string"}, "default": ["a", "b"]},
+			"limits": {"type": ["object", "null"], "additionalProperties": {"type": "boolean"}, "default": {"a": true}},
+			"timeout": {"type": "string", "default": "5s"},
+			"db": {
+				"type": "object",
+				"properties": {"start": {"type": "string", "format": "date-time"}},
+				"additionalProperties": false
+			}
+		},
+		"required": ["host"],
+		"additionalProperties": false
+	}`, string(data))
+}
+
+func Test_JSONSchema_keepsFieldOrder(t *testing.T) {
+	// Arrange
+	config := &struct {
+		B int `env:"b"`
+		A int `env:"a"`
+	}{}
+
+	// Act
+	data, err := JSONSchema(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.True(t, json.Valid(data))
+	require.Regexp(t, `(?s)"b".*"a"`, string(data))
+}
+
+func Test_JSONSchema_error(t *testing.T) {
+	// Arrange
+	config := struct{}{}
+
+	// Act
+	_, err := JSONSchema(config)
+
+	// Assert
+	require.NotNil(t, err)
+}
@@ -0,0 +1,88 @@
+package configuration
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Replace secret file references in the intermediate tree with the file contents
+// only the value which is set to the field is resolved, references overridden by other sources are not read
+// the reference is kept in the "ref" field of the intermediate data
+func (cr *configReader) resolveSecretFiles(it intermediateTree, si []structInfo) error {
+	for _, info := range si {
+		data := it[info.keyName]
+		if info.isSlice || info.isMap || len(data) == 0 {
+			continue
+		}
+		i := len(data) - 1
+		if !cr.options.RewriteValues {
+			i = 0
+		}
+		value, ok := data[i].value.(string)
+		if !ok || data[i].valueType == vtNull || cr.isStructSource(data[i].source) {
+			continue
+		}
+		secret, isRef, err := cr.resolveSecretValue(info, value)
+		if err != nil {
+			return err
+		}
+		if isRef {
+			data[i].value = secret
+			data[i].ref = value
+		}
+	}
+	return nil
+}
+
+// Read the secret if the value is a secret file reference
+// returns the secret, whether the value was a reference and an error
+func (cr *configReader) resolveSecretValue(info structInfo, value string) (string, bool, error) {
+	path := ""
+	if info.secretFile {
+		path = value
+	} else if info.isSecret && isSecretFileReference(value) {
+		path = value[len(secretFilePrefix):]
+	}
+	if path == "" || path == nilDefault {
+		return value, false, nil
+	}
+
+	secret, err := cr.readSecretFile(path)
+	if err != nil {
+		return "", false, errors.New("can't read secret file for field " + info.fieldName + ": " + err.Error())
+	}
+	return secret, true, nil
+}
+
+func isSecretFileReference(value string) bool {
+	return strings.HasPrefix(value, secretFilePrefix) && !strings.HasPrefix(value, secretFilePrefix+"//")
+}
+
+func (cr *configReader) readSecretFile(path string) (string, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if stat.IsDir() {
+		return "", errors.New(path + " is a directory")
+	}
+
+	maxSize := cr.options.SecretFileMaxSize
+	if maxSize <= 0 {
+		maxSize = secretFileMaxSize
+	}
+	if stat.Size() > maxSize {
+		return "", errors.New(path + " is larger than " + strconv.FormatInt(maxSize, 10) + " bytes")
+	}
+	if cr.options.StrictSecretFiles && stat.Mode().Perm()&0o004 != 0 {
+		return "", errors.New(path + " is world-readable")
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return trimTrailingNewLine(string(b)), nil
+}
@@ -0,0 +1,149 @@
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeSecretFile(t *testing.T, name, content string, perm os.FileMode) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.Nil(t, os.WriteFile(path, []byte(content), perm))
+	require.Nil(t, os.Chmod(path, perm))
+	return path
+}
+
+func Test_ReadConfig_secretFile_success(t *testing.T) {
+	// Arrange
+	password := writeSecretFile(t, "db_password", "p@ss\n", 0o600)
+	token := writeSecretFile(t, "token", "t0ken\r\n", 0o600)
+	def := writeSecretFile(t, "def", "default\n", 0o600)
+	config := &struct {
+		Password string `env:"password,secret"`
+		Token    string `env:"token,secretfile"`
+		Def      string `env:"def,secretfile"`
+		Url      string `env:"url,secret"`
+		Literal  string `env:"literal"`
+	}{}
+	config2 := &struct {
+		Def string `env:"def,secret"`
+	}{}
+	cr := NewConfigReader().
+		AddString("password = file:"+password+"\ntoken = "+token+"\nurl = file:///tmp/x\nliteral = file:"+password, FtEnv, "test")
+
+	// Act
+	err := cr.ReadConfig(config)
+	if err == nil {
+		err = NewConfigReader().AddString("def = 'file:"+def+"'", FtEnv, "test").ReadConfig(config2)
+	}
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "p@ss", config.Password)
+	require.Equal(t, "t0ken", config.Token)
+	require.Equal(t, "", config.Def)
+	require.Equal(t, "file:///tmp/x", config.Url)
+	require.Equal(t, "file:"+password, config.Literal)
+	require.Equal(t, "default", config2.Def)
+}
+
+func Test_resolveSecretFiles_keepsReference(t *testing.T) {
+	// Arrange
+	password := writeSecretFile(t, "db_password", "p@ss\n", 0o600)
+	cr := &configReader{}
+	si := []structInfo{{keyName: "password", fieldName: "Password", isSecret: true}}
+	it := intermediateTree{
+		"password": []intermediateData{{value: "file:" + password, source: 0, valueType: vtAny}},
+	}
+
+	// Act
+	err := cr.resolveSecretFiles(it, si)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "p@ss", it["password"][0].value)
+	require.Equal(t, "file:"+password, it["password"][0].ref)
+}
+
+type testCaseSecretFileOverridden struct {
+	rewrite bool
+	base    string
+	site    string
+	pw      string
+	token   string
+}
+
+func Test_ReadConfig_secretFile_overridden_cases(t *testing.T) {
+	// Arrange
+	missing := filepath.Join(t.TempDir(), "missing")
+	password := writeSecretFile(t, "db_password", "p@ss\n", 0o600)
+	cases := []testCaseSecretFileOverridden{
+		{true, "pw = file:" + missing + "\ntoken = " + missing, "pw = dev\ntoken = " + password, "dev", "p@ss"},
+		{false, "pw = dev\ntoken = " + password, "pw = file:" + missing + "\ntoken = " + missing, "dev", "p@ss"},
+	}
+
+	// Act & Assert
+	for i, c := range cases {
+		t.Log("Test case:", i)
+		test_ReadConfig_secretFile_overridden(t, c)
+	}
+}
+
+func test_ReadConfig_secretFile_overridden(t *testing.T, testCase testCaseSecretFileOverridden) {
+	// Arrange
+	config := &struct {
+		Pw    string `env:"pw,secret"`
+		Token string `env:"token,secretfile"`
+	}{}
+
+	// Act
+	err := NewConfigReader().
+		RewriteValues(testCase.rewrite).
+		AddString(testCase.base, FtEnv, "base").
+		AddString(testCase.site, FtEnv, "site").
+		ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, testCase.pw, config.Pw)
+	require.Equal(t, testCase.token, config.Token)
+}
+
+type testCaseSecretFileError struct {
+	content string
+	perm    os.FileMode
+	options ConfigOptions
+	err     string
+}
+
+func Test_readSecretFile_error_cases(t *testing.T) {
+	// Arrange
+	cases := []testCaseSecretFileError{
+		{"s3cr3t", 0o644, ConfigOptions{StrictSecretFiles: true}, "is world-readable"},
+		{"s3cr3t", 0o600, ConfigOptions{SecretFileMaxSize: 3}, "is larger than 3 bytes"},
+		{strings.Repeat("s", secretFileMaxSize+1), 0o600, ConfigOptions{}, "is larger than 65536 bytes"},
+	}
+
+	// Act & Assert
+	for i, c := range cases {
+		t.Log("Test case:", i)
+		test_readSecretFile_error(t, c)
+	}
+}
+
+func test_readSecretFile_error(t *testing.T, testCase testCaseSecretFileError) {
+	// Arrange
+	path := writeSecretFile(t, "secret", testCase.content, testCase.perm)
+	cr := &configReader{options: testCase.options}
+
+	// Act
+	_, err := cr.readSecretFile(path)
+
+	// Assert
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), testCase.err)
+	require.NotContains(t, err.Error(), testCase.content)
+}
@@ -0,0 +1,127 @@
+package configuration
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Option of a configuration source, passed to AddFile, AddString, AddEnvironment and AddKeyPerFileDir
+type SourceOption func(*configSource)
+
+// Set the priority of the source
+// sources with higher priority are read later and override the others,
+// sources with the same priority keep the order they were added in
+// priority - priority of the source, default is 0
+func WithPriority(priority int) SourceOption {
+	return func(source *configSource) {
+		source.priority = priority
+	}
+}
+
+// Set the name of the layer the source belongs to, e.g. "defaults" or "site"
+// the name is shown with the source name by Explain
+// name - layer name
+func WithLayer(name string) SourceOption {
+	return func(source *configSource) {
+		source.layer = name
+	}
+}
+
+// Mount the source under the key prefix, every key read from the source gets it
+// e.g. "host" of redis.ini is "cache.redis.host" with AtPrefix("cache.redis")
+// for environment variables the prefix is removed from the key name, "cache.redis.host" is read from "host"
+// prefix - key prefix
+func AtPrefix(prefix string) SourceOption {
+	return func(source *configSource) {
+		source.atPrefix = normalizePrefix(prefix)
+	}
+}
+
+// Remove the key prefix from the keys of the source, e.g. a top-level wrapper object of a json file
+// keys without the prefix are read as they are, the prefix is removed before AtPrefix is added
+// prefix - key prefix
+func StripPrefix(prefix string) SourceOption {
+	return func(source *configSource) {
+		source.stripPrefix = normalizePrefix(prefix)
+	}
+}
+
+// the case of the prefix is changed when the source is read, see CaseSensitive
+func normalizePrefix(prefix string) string {
+	return strings.Trim(prefix, ".")
+}
+
+func (cr *configReader) addSource(source configSource, options []SourceOption) *configReader {
+	for _, option := range options {
+		option(&source)
+	}
+	cr.sources = append(cr.sources, source)
+	return cr
+}
+
+// Get the sources in the order they are read
+func (cr *configReader) orderedSources() []configSource {
+	sources := slices.Clone(cr.sources)
+	slices.SortStableFunc(sources, func(a, b configSource) int {
+		return cmp.Compare(a.priority, b.priority)
+	})
+	return sources
+}
+
+// Get the key name for the key read from the current source
+func (cr *configReader) sourceKeyName(name string) string {
+	if cr.data.stripPrefix != "" {
+		if rest, ok := strings.CutPrefix(name, cr.data.stripPrefix+"."); ok {
+			name = rest
+		}
+	}
+	if cr.data.atPrefix != "" {
+		name = cr.data.atPrefix + "." + name
+	}
+	return name
+}
+
+// Get the name of the environment variable for the key, false if the key is out of the source prefix
+func (cr *configReader) sourceVariableName(keyName string) (string, bool) {
+	if cr.data.atPrefix != "" {
+		rest, ok := strings.CutPrefix(keyName, cr.data.atPrefix+".")
+		if !ok {
+			return "", false
+		}
+		keyName = rest
+	}
+	if cr.data.stripPrefix != "" {
+		keyName = cr.data.stripPrefix + "." + keyName
+	}
+	return keyName, true
+}
+
+// Whether the json key is the wrapper removed by StripPrefix or its parent
+// name - key name with the prefix added by AtPrefix
+func (cr *configReader) isStrippedKey(name string) bool {
+	wrapper := cr.jsonWrapper()
+	return wrapper != "" && (name == wrapper || strings.HasPrefix(wrapper, name+"."))
+}
+
+// Get the prefix of the json object, the wrapper removed by StripPrefix gives the root prefix of the source
+func (cr *configReader) sourceJsonPrefix(prefix string) string {
+	if wrapper := cr.jsonWrapper(); wrapper != "" && prefix == wrapper+"." {
+		return cr.sourceRootPrefix()
+	}
+	return prefix
+}
+
+func (cr *configReader) jsonWrapper() string {
+	if cr.data.stripPrefix == "" {
+		return ""
+	}
+	return cr.sourceRootPrefix() + cr.data.stripPrefix
+}
+
+func (cr *configReader) sourceRootPrefix() string {
+	if cr.data.atPrefix == "" {
+		return ""
+	}
+	return cr.data.atPrefix + "."
+}
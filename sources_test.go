@@ -0,0 +1,127 @@
+package configuration
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReadConfig_priority_success(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Host  string   `env:"host"`
+		Port  int      `env:"port"`
+		Hosts []string `env:"hosts" merge:"append"`
+	}{}
+	t.Setenv("host", "env")
+	cr := NewConfigReader().
+		AddEnvironment(WithPriority(100), WithLayer("env")).
+		AddString("host = site\nport = 2\nhosts = b", FtEnv, "site.env", WithLayer("site")).
+		AddString("host = lib\nport = 1\nhosts = a", FtEnv, "lib.env", WithPriority(-10), WithLayer("defaults")).
+		AddString("hosts = c", FtEnv, "other.env")
+
+	// Act
+	err := cr.ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "env", config.Host)
+	require.Equal(t, 2, config.Port)
+	require.Equal(t, []string{"a", "b", "c"}, config.Hosts)
+
+	explanation, err := cr.Explain(config)
+	require.Nil(t, err)
+	require.Equal(t, "env: environment", explanation[0].Source)
+	require.Equal(t, "site: site.env", explanation[1].Source)
+	require.Equal(t, "defaults: lib.env, site: site.env, other.env", explanation[2].Source)
+}
+
+func Test_ReadTree_priority(t *testing.T) {
+	// Arrange
+	cr := NewConfigReader().
+		AddString("host = b", FtEnv, "b", WithPriority(1)).
+		AddString("host = a", FtEnv, "a")
+
+	// Act
+	tree, err := cr.ReadTree()
+
+	// Assert
+	require.Nil(t, err)
+	host, _ := tree.GetString("host")
+	require.Equal(t, "b", host)
+}
+
+func Test_orderedSources_extreme_priorities(t *testing.T) {
+	// Arrange
+	cr := NewConfigReader().
+		AddString("", FtEnv, "max", WithPriority(math.MaxInt)).
+		AddString("", FtEnv, "min", WithPriority(math.MinInt)).
+		AddString("", FtEnv, "zero").
+		AddString("", FtEnv, "max 2", WithPriority(math.MaxInt))
+
+	// Act
+	sources := cr.orderedSources()
+
+	// Assert
+	names := make([]string, 0, len(sources))
+	for _, source := range sources {
+		names = append(names, source.name)
+	}
+	require.Equal(t, []string{"min", "zero", "max", "max 2"}, names)
+}
+
+func Test_ReadConfig_prefix_success(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Host  string `env:"host"`
+		Cache struct {
+			Redis struct {
+				Host  string   `env:"host"`
+				Port  int      `env:"port"`
+				Hosts []string `env:"hosts"`
+				Db    struct {
+					Index int `env:"index"`
+				} `env:"db"`
+			} `env:"redis"`
+		} `env:"cache"`
+	}{}
+	t.Setenv("port", "6380")
+	cr := NewConfigReader().
+		AddString("host = r\nhosts[] = a\n[db]\nindex = 1", FtIni, "redis.ini", AtPrefix("cache.redis")).
+		AddString(`{"app": {"host": "h", "cache": {"redis": {"db": {"index": 2}}}}}`, FtJson, "app.json", StripPrefix("app")).
+		AddString(`{"Wrapper": {"hosts": ["b"]}}`, FtJson, "wrapped.json", StripPrefix("wrapper"), AtPrefix(".Cache.Redis.")).
+		AddEnvironment(AtPrefix("cache.redis")).
+		Strict(true)
+
+	// Act
+	err := cr.ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "h", config.Host)
+	require.Equal(t, "r", config.Cache.Redis.Host)
+	require.Equal(t, 6380, config.Cache.Redis.Port)
+	require.Equal(t, []string{"b"}, config.Cache.Redis.Hosts)
+	require.Equal(t, 2, config.Cache.Redis.Db.Index)
+}
+
+func Test_ReadConfig_prefix_env_strip(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Host string `env:"host"`
+		Port int    `env:"port"`
+	}{}
+	t.Setenv("myapp.port", "80")
+
+	// Act
+	err := NewConfigReader().
+		AddString("myapp.host = h", FtEnv, "app.env", StripPrefix("myapp")).
+		AddEnvironment(StripPrefix("myapp")).
+		ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "h", config.Host)
+	require.Equal(t, 80, config.Port)
+}
@@ -0,0 +1,611 @@
+package configuration
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var jsonNumberRegexp = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// Configuration keys read from all the sources without a user config struct
+type Tree struct {
+	keys          []string // in order of appearance in the sources
+	values        map[string]treeValue
+	caseSensitive bool
+}
+
+type treeValue struct {
+	value     interface{} // string, []string or map[string]string
+	valueType valueType
+	source    string
+	line      int
+}
+
+// Option of ReadTree
+type TreeOption func(*treeOptions)
+
+type treeOptions struct {
+	appendCollections bool
+}
+
+// Append collections from all the sources as if every collection had the "append" option
+// slices get the items of all the sources, maps get the keys of all the sources
+func AppendCollections() TreeOption {
+	return func(options *treeOptions) {
+		options.appendCollections = true
+	}
+}
+
+// Read configuration from all the sources without a user config struct
+// values are merged by the same rules as in ReadConfig for fields without merge tag
+// environment source can override only the keys from other sources
+// options - merge options, e.g. AppendCollections()
+func (cr *configReader) ReadTree(options ...TreeOption) (*Tree, error) {
+	treeOpts := treeOptions{}
+	for _, option := range options {
+		option(&treeOpts)
+	}
+
+	cr.data.schemaless = true
+	defer func() { cr.data.schemaless = false }()
+
+	it := intermediateTree{}
+	err := cr.readSources(it, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cr.data.lastTree = it
+	cr.data.lastSources = cr.orderedSources()
+
+	return cr.newTree(it, treeOpts), nil
+}
+
+// Get the struct info for the key read without the user config struct
+// the key can't be a value and a collection in the same source
+func (cr *configReader) getSchemalessInfo(it intermediateTree, name string, isMap, isSlice bool, sourceId int) (structInfo, error) {
+	for _, d := range it[name] {
+		if d.source != sourceId {
+			continue
+		}
+		switch d.value.(type) {
+		case []string:
+			isSlice, isMap = true, false
+		case map[string]string:
+			isSlice, isMap = false, true
+		default:
+			if isSlice || isMap {
+				return structInfo{}, errors.New("key \"" + name + "\" is used as a value and as a collection " + cr.currentPointInfo())
+			}
+		}
+	}
+	return newTreeStructInfo(name, isSlice, isMap), nil
+}
+
+func newTreeStructInfo(name string, isSlice, isMap bool) structInfo {
+	return structInfo{
+		fieldName:  name,
+		keyName:    name,
+		separator:  sepDefault,
+		separator2: sep2Default,
+		isSlice:    isSlice,
+		isMap:      isMap,
+	}
+}
+
+// Get the struct info for the keys which are already in the tree
+func getTreeStructInfo(it intermediateTree) []structInfo {
+	si := make([]structInfo, 0, len(it))
+	for name, data := range it {
+		if len(data) == 0 {
+			continue
+		}
+		_, isSlice := data[len(data)-1].value.([]string)
+		_, isMap := data[len(data)-1].value.(map[string]string)
+		si = append(si, newTreeStructInfo(name, isSlice, isMap))
+	}
+	return si
+}
+
+func (cr *configReader) newTree(it intermediateTree, options treeOptions) *Tree {
+	tree := &Tree{keys: make([]string, 0, len(it)), values: map[string]treeValue{}, caseSensitive: cr.options.CaseSensitive}
+	for name, data := range it {
+		if len(data) == 0 {
+			continue
+		}
+		tree.keys = append(tree.keys, name)
+		tree.values[name] = cr.mergeTreeData(name, data, options)
+	}
+
+	sort.Slice(tree.keys, func(i, j int) bool {
+		a, b := it[tree.keys[i]][0], it[tree.keys[j]][0]
+		if a.source != b.source {
+			return a.source < b.source
+		} else if a.line != b.line {
+			return a.line < b.line
+		}
+		return tree.keys[i] < tree.keys[j]
+	})
+	return tree
+}
+
+// Merge the values of the key from all the sources
+func (cr *configReader) mergeTreeData(name string, data []intermediateData, options treeOptions) treeValue {
+	last := data[len(data)-1]
+	_, isSlice := last.value.([]string)
+	_, isMap := last.value.(map[string]string)
+	info := newTreeStructInfo(name, isSlice, isMap)
+	info.merge, _ = getMergeStrategy("", options.appendCollections, isSlice, isMap, name)
+
+	effective := cr.effectiveData(info, data)
+	if len(effective) == 0 {
+		effective = data[len(data)-1:]
+	}
+
+	result := treeValue{valueType: effective[len(effective)-1].valueType, line: effective[len(effective)-1].line}
+	names := make([]string, 0, len(effective))
+	values := []string{}
+	items := map[string]string{}
+	for _, d := range effective {
+		switch value := d.value.(type) {
+		case []string:
+			if !isSlice {
+				continue
+			}
+			values = append(values, value...)
+		case map[string]string:
+			if !isMap {
+				continue
+			}
+			for k, v := range value {
+				items[k] = v
+			}
+		default:
+			if isSlice || isMap {
+				continue
+			}
+			result.value = value
+		}
+		names = append(names, cr.sourceName(d.source))
+	}
+	if isSlice {
+		result.value = values
+	} else if isMap {
+		result.value = items
+	}
+	result.source = strings.Join(names, ", ")
+	return result
+}
+
+// Get keys of the tree starting with the prefix in alphabetical order
+// prefix - key prefix, e.g. "db" for "db.host" and "db.port", all keys if empty
+func (t *Tree) Keys(prefix string) []string {
+	prefix = t.keyCase(prefix)
+	keys := []string{}
+	for _, key := range t.keys {
+		if prefix == "" || key == prefix || strings.HasPrefix(key, prefix+".") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Get the raw value of the key: string, []string or map[string]string
+// key - key name, e.g. "db.host"
+func (t *Tree) Get(key string) (interface{}, bool) {
+	v, ok := t.values[t.keyCase(key)]
+	if !ok {
+		return nil, false
+	}
+	switch value := v.value.(type) {
+	case []string:
+		return append([]string{}, value...), true
+	case map[string]string:
+		items := make(map[string]string, len(value))
+		for k, item := range value {
+			items[k] = item
+		}
+		return items, true
+	}
+	return v.value, true
+}
+
+// Set the value of the key, used by migrations
+// key - key name, e.g. "db.host"
+// value - string, []string or map[string]string
+func (t *Tree) Set(key string, value interface{}) error {
+	key = t.keyCase(key)
+	switch value.(type) {
+	case string, []string, map[string]string:
+	default:
+		return errors.New("unsupported value type of key " + key)
+	}
+
+	v, ok := t.values[key]
+	if !ok {
+		t.keys = append(t.keys, key)
+	}
+	v.value = value
+	v.valueType = vtAny
+	t.values[key] = v
+	return nil
+}
+
+// Delete the key and every key under it, used by migrations
+// key - key name or section name, e.g. "db"
+func (t *Tree) Delete(key string) {
+	key = t.keyCase(key)
+	keys := t.keys[:0]
+	for _, k := range t.keys {
+		if k == key || strings.HasPrefix(k, key+".") {
+			delete(t.values, k)
+		} else {
+			keys = append(keys, k)
+		}
+	}
+	t.keys = keys
+}
+
+// Rename the key and every key under it, e.g. to move keys into a section, used by migrations
+// existing keys with the new name are replaced
+// from - key name or section name, e.g. "host"
+// to - new name, e.g. "db.host"
+func (t *Tree) Rename(from, to string) error {
+	from, to = t.keyCase(from), t.keyCase(to)
+	renamed := map[string]treeValue{}
+	for _, k := range t.keys {
+		if k == from {
+			renamed[to] = t.values[k]
+		} else if strings.HasPrefix(k, from+".") {
+			renamed[to+k[len(from):]] = t.values[k]
+		}
+	}
+	if len(renamed) == 0 {
+		return errors.New("key " + from + " not found")
+	}
+
+	for k := range renamed {
+		if k != from && !strings.HasPrefix(k, from+".") {
+			t.Delete(k)
+		}
+	}
+	for i, k := range t.keys {
+		if k == from {
+			t.keys[i] = to
+		} else if strings.HasPrefix(k, from+".") {
+			t.keys[i] = to + k[len(from):]
+		} else {
+			continue
+		}
+		delete(t.values, k)
+	}
+	for k, v := range renamed {
+		t.values[k] = v
+	}
+	return nil
+}
+
+// Get keys and values as nested maps, sections are map[string]interface{}
+// json values keep their types (float64, bool, nil), env and ini values are strings
+func (t *Tree) Map() map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, key := range t.Keys("") {
+		node := result
+		path := strings.Split(key, ".")
+		for _, name := range path[:len(path)-1] {
+			child, ok := node[name].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				node[name] = child
+			}
+			node = child
+		}
+		name := path[len(path)-1]
+		if _, ok := node[name].(map[string]interface{}); ok {
+			continue
+		}
+
+		v := t.values[key]
+		switch value := v.value.(type) {
+		case string:
+			node[name] = typedTreeValue(value, v.valueType)
+		case []string:
+			items := make([]interface{}, 0, len(value))
+			for _, item := range value {
+				items = append(items, typedTreeValue(item, v.valueType))
+			}
+			node[name] = items
+		case map[string]string:
+			items := make(map[string]interface{}, len(value))
+			for k, item := range value {
+				items[k] = typedTreeValue(item, v.valueType)
+			}
+			node[name] = items
+		}
+	}
+	return result
+}
+
+func typedTreeValue(value string, vType valueType) interface{} {
+	if value == nilDefault && vType != vtString {
+		return nil
+	}
+	switch vType {
+	case vtNumber:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case vtBool:
+		return strings.ToLower(value) == "true"
+	}
+	return value
+}
+
+// Get a part of the tree under the prefix, the prefix is removed from the keys
+// prefix - section name, e.g. "db"
+func (t *Tree) Sub(prefix string) *Tree {
+	prefix = t.keyCase(prefix) + "."
+	sub := &Tree{keys: []string{}, values: map[string]treeValue{}, caseSensitive: t.caseSensitive}
+	for _, key := range t.keys {
+		if strings.HasPrefix(key, prefix) {
+			sub.keys = append(sub.keys, key[len(prefix):])
+			sub.values[key[len(prefix):]] = t.values[key]
+		}
+	}
+	return sub
+}
+
+// Get the source names and the line of the key value
+// returns false if there is no such key
+func (t *Tree) Origin(key string) (string, int, bool) {
+	v, ok := t.values[t.keyCase(key)]
+	return v.source, v.line, ok
+}
+
+// Get the value of the key as a string
+// key - key name, e.g. "db.host"
+func (t *Tree) GetString(key string) (string, error) {
+	v, err := t.getScalar(key)
+	if err != nil || v.isNull() {
+		return "", err
+	}
+	return v.value.(string), nil
+}
+
+// Get the value of the key as an int
+// key - key name, e.g. "db.port"
+func (t *Tree) GetInt(key string) (int, error) {
+	v, err := t.getScalar(key)
+	if err != nil || v.isNull() {
+		return 0, err
+	}
+	i, err := strconv.Atoi(v.value.(string))
+	if err != nil {
+		return 0, treeConvertError(key, "int", err)
+	}
+	return i, nil
+}
+
+// Get the value of the key as a float64
+// key - key name, e.g. "ratio"
+func (t *Tree) GetFloat(key string) (float64, error) {
+	v, err := t.getScalar(key)
+	if err != nil || v.isNull() {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(v.value.(string), 64)
+	if err != nil {
+		return 0, treeConvertError(key, "float64", err)
+	}
+	return f, nil
+}
+
+// Get the value of the key as a bool
+// key - key name, e.g. "debug"
+func (t *Tree) GetBool(key string) (bool, error) {
+	v, err := t.getScalar(key)
+	if err != nil || v.isNull() {
+		return false, err
+	}
+	b, err := strconv.ParseBool(v.value.(string))
+	if err != nil {
+		return false, treeConvertError(key, "bool", err)
+	}
+	return b, nil
+}
+
+// Get the value of the key as a time.Duration, e.g. "1m30s"
+// key - key name, e.g. "db.timeout"
+func (t *Tree) GetDuration(key string) (time.Duration, error) {
+	v, err := t.getScalar(key)
+	if err != nil || v.isNull() {
+		return 0, err
+	}
+	d, err := time.ParseDuration(v.value.(string))
+	if err != nil {
+		return 0, treeConvertError(key, "time.Duration", err)
+	}
+	return d, nil
+}
+
+// Get the value of the key as a slice of strings
+// single values are split by "," like for the slice fields
+// key - key name, e.g. "hosts"
+func (t *Tree) GetStringSlice(key string) ([]string, error) {
+	v, ok := t.values[t.keyCase(key)]
+	if !ok {
+		return nil, errors.New("key " + key + " not found")
+	}
+	switch value := v.value.(type) {
+	case []string:
+		return append([]string{}, value...), nil
+	case string:
+		if v.isNull() {
+			return nil, nil
+		} else if value == "" {
+			return []string{}, nil
+		}
+		return strings.Split(value, sepDefault), nil
+	}
+	return nil, errors.New("key " + key + " is a map")
+}
+
+func (t *Tree) getScalar(key string) (treeValue, error) {
+	v, ok := t.values[t.keyCase(key)]
+	if !ok {
+		return treeValue{}, errors.New("key " + key + " not found")
+	}
+	if _, ok := v.value.(string); !ok {
+		return treeValue{}, errors.New("key " + key + " is a collection")
+	}
+	return v, nil
+}
+
+// Get the key name in the case of the tree keys
+func (t *Tree) keyCase(key string) string {
+	if t.caseSensitive {
+		return key
+	}
+	return strings.ToLower(key)
+}
+
+func (v treeValue) isNull() bool {
+	return v.value == nilDefault && (v.valueType == vtNull || v.valueType == vtAny)
+}
+
+func treeConvertError(key, typeName string, err error) error {
+	return errors.New("can't convert value of key " + key + " to " + typeName + ": " + err.Error())
+}
+
+// Write the tree in env, ini or json format
+// values from env and ini sources are written to json as numbers and booleans if they look like them
+// formatType - format to write
+func (t *Tree) Marshal(formatType formatType) ([]byte, error) {
+	return marshalItems(t.marshalItems(), formatType)
+}
+
+func (t *Tree) marshalItems() []marshalItem {
+	items := make([]marshalItem, 0, len(t.keys))
+	for _, key := range t.keys {
+		v := t.values[key]
+		item := marshalItem{
+			key:        key,
+			value:      v.value,
+			valueType:  v.valueType,
+			separator:  sepDefault,
+			separator2: sep2Default,
+			isNullable: true,
+		}
+		_, item.isMap = v.value.(map[string]string)
+		item.valueType = inferItemValueType(v.value, v.valueType)
+		items = append(items, item)
+	}
+	return items
+}
+
+// Explain where the values of the tree came from
+func (t *Tree) Explain() Explanation {
+	result := make(Explanation, 0, len(t.keys))
+	for _, key := range t.Keys("") {
+		v := t.values[key]
+		entry := ExplainEntry{
+			Key:    key,
+			Type:   v.typeName(),
+			Source: v.source,
+			Line:   v.line,
+		}
+		switch value := v.value.(type) {
+		case string:
+			entry.Value = value
+		case []string:
+			entry.Value = "[" + strings.Join(value, ", ") + "]"
+		case map[string]string:
+			pairs := make([]string, 0, len(value))
+			for _, k := range sortedMapKeys(value) {
+				pairs = append(pairs, k+":"+value[k])
+			}
+			entry.Value = "{" + strings.Join(pairs, ", ") + "}"
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+func (v treeValue) typeName() string {
+	name := ""
+	switch v.valueType {
+	case vtString:
+		name = "string"
+	case vtNumber:
+		name = "number"
+	case vtBool:
+		name = "bool"
+	case vtNull:
+		name = "null"
+	default:
+		name = "any"
+	}
+	switch v.value.(type) {
+	case []string:
+		return "[]" + name
+	case map[string]string:
+		return "map[string]" + name
+	}
+	return name
+}
+
+// Get the type of the value or collection items, vtAny values are inferred from the text
+func inferItemValueType(value interface{}, vType valueType) valueType {
+	switch value := value.(type) {
+	case string:
+		if vType == vtAny {
+			return inferValueType(value)
+		}
+	case []string:
+		return inferCollectionType(value, vType)
+	case map[string]string:
+		values := make([]string, 0, len(value))
+		for _, k := range sortedMapKeys(value) {
+			values = append(values, value[k])
+		}
+		return inferCollectionType(values, vType)
+	}
+	return vType
+}
+
+// Guess the json type of the value from env or ini source
+func inferValueType(value string) valueType {
+	if value == "true" || value == "false" {
+		return vtBool
+	} else if value == nilDefault {
+		return vtNull
+	} else if jsonNumberRegexp.MatchString(value) {
+		return vtNumber
+	}
+	return vtString
+}
+
+func inferCollectionType(values []string, vType valueType) valueType {
+	if vType != vtAny && vType != vtNull {
+		return vType
+	}
+	result := vtEmpty
+	for _, v := range values {
+		t := inferValueType(v)
+		if t == vtNull {
+			continue
+		} else if result != vtEmpty && result != t {
+			return vtString
+		}
+		result = t
+	}
+	if result == vtEmpty {
+		return vtString
+	}
+	return result
+}
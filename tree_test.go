@@ -0,0 +1,202 @@
+package configuration
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReadTree_success(t *testing.T) {
+	// Arrange
+	cr := NewConfigReader().
+		AddString("host = h\nport = 80\nhosts[] = a\nlimits[x] = 1\n[db]\nuser = u\n", FtIni, "ini").
+		AddString(`{"port": 81, "hosts": ["b"], "db": {"pass": "p"}, "n": null}`, FtJson, "json")
+
+	// Act
+	tree, err := cr.ReadTree()
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, []string{"db.pass", "db.user", "host", "hosts", "limits", "n", "port"}, tree.Keys(""))
+	require.Equal(t, []string{"db.pass", "db.user"}, tree.Keys("DB"))
+	port, _ := tree.Get("port")
+	require.Equal(t, "81", port)
+	hosts, _ := tree.Get("hosts")
+	require.Equal(t, []string{"b"}, hosts)
+	limits, _ := tree.Get("limits")
+	require.Equal(t, map[string]string{"x": "1"}, limits)
+	_, ok := tree.Get("unknown")
+	require.False(t, ok)
+}
+
+type testCaseTreeMerge struct {
+	rewrite     bool
+	append      bool
+	port        string
+	hosts       []string
+	hostsOrigin string
+}
+
+func Test_ReadTree_merge_cases(t *testing.T) {
+	// Arrange
+	cases := []testCaseTreeMerge{
+		{true, false, "2", []string{"c"}, "b"},
+		{false, false, "1", []string{"c"}, "b"},
+		{true, true, "2", []string{"a", "b", "c"}, "a, b"},
+	}
+
+	// Act & Assert
+	for i, c := range cases {
+		t.Log("Test case:", i)
+		test_ReadTree_merge(t, c)
+	}
+}
+
+func test_ReadTree_merge(t *testing.T, testCase testCaseTreeMerge) {
+	// Arrange
+	cr := NewConfigReader().
+		RewriteValues(testCase.rewrite).
+		AddString("port = 1\nhosts[] = a\nhosts[] = b", FtEnv, "a").
+		AddString("port = 2\nhosts[] = c", FtEnv, "b")
+	options := []TreeOption{}
+	if testCase.append {
+		options = append(options, AppendCollections())
+	}
+
+	// Act
+	tree, err := cr.ReadTree(options...)
+
+	// Assert
+	require.Nil(t, err)
+	port, _ := tree.Get("port")
+	require.Equal(t, testCase.port, port)
+	hosts, _ := tree.Get("hosts")
+	require.Equal(t, testCase.hosts, hosts)
+	require.Equal(t, testCase.hostsOrigin, tree.Explain()[0].Source)
+}
+
+func Test_ReadTree_environment(t *testing.T) {
+	// Arrange
+	t.Setenv("tree.host", "env")
+	t.Setenv("tree.other", "env")
+	cr := NewConfigReader().AddString("tree.host = h", FtEnv, "a").AddEnvironment()
+
+	// Act
+	tree, err := cr.ReadTree()
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, []string{"tree.host"}, tree.Keys(""))
+	host, _ := tree.Get("tree.host")
+	require.Equal(t, "env", host)
+}
+
+func Test_ReadTree_error(t *testing.T) {
+	// Arrange
+	cr := NewConfigReader().AddString("key = a\nkey[] = b", FtEnv, "a")
+
+	// Act
+	_, err := cr.ReadTree()
+
+	// Assert
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "key \"key\" is used as a value and as a collection")
+}
+
+func Test_Tree_Marshal_json(t *testing.T) {
+	// Arrange
+	tree, err := NewConfigReader().
+		AddString("port = 80\non = true\nname = 007\nids = 1,2\nmixed[] = 1\nmixed[] = a\nptr = *nil\n[db]\nuser = u\n", FtIni, "a").
+		ReadTree()
+	require.Nil(t, err)
+
+	// Act
+	data, err := tree.Marshal(FtJson)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "{\n  \"port\": 80,\n  \"on\": true,\n  \"name\": \"007\",\n  \"ids\": \"1,2\",\n"+
+		"  \"mixed\": [\"1\", \"a\"],\n  \"ptr\": null,\n  \"db\": {\n    \"user\": \"u\"\n  }\n}\n", string(data))
+}
+
+func Test_Tree_accessors(t *testing.T) {
+	// Arrange
+	tree, err := NewConfigReader().
+		AddString("port = 80\nratio = 0.5\ndebug = true\ntimeout = 1m30s\nhosts = a,b\nids[] = 1\nptr = *nil\n[db]\nhost = h\n", FtIni, "a").
+		AddString(`{"db": {"port": 5432, "tags": {"a": "x"}}}`, FtJson, "b").
+		ReadTree()
+	require.Nil(t, err)
+
+	// Act
+	port, errPort := tree.GetInt("port")
+	ratio, errRatio := tree.GetFloat("ratio")
+	debug, errDebug := tree.GetBool("debug")
+	timeout, errTimeout := tree.GetDuration("timeout")
+	hosts, errHosts := tree.GetStringSlice("hosts")
+	ids, errIds := tree.GetStringSlice("ids")
+	ptr, errPtr := tree.GetString("ptr")
+	dbPort, errDbPort := tree.Sub("db").GetInt("port")
+	source, line, ok := tree.Origin("db.host")
+
+	// Assert
+	require.Nil(t, errors.Join(errPort, errRatio, errDebug, errTimeout, errHosts, errIds, errPtr, errDbPort))
+	require.Equal(t, 80, port)
+	require.Equal(t, 0.5, ratio)
+	require.True(t, debug)
+	require.Equal(t, 90*time.Second, timeout)
+	require.Equal(t, []string{"a", "b"}, hosts)
+	require.Equal(t, []string{"1"}, ids)
+	require.Equal(t, "", ptr)
+	require.Equal(t, 5432, dbPort)
+	require.Equal(t, []string{"host", "port", "tags.a"}, tree.Sub("db").Keys(""))
+	require.Equal(t, "a", source)
+	require.Equal(t, 9, line)
+	require.True(t, ok)
+}
+
+func Test_Tree_accessors_error_cases(t *testing.T) {
+	// Arrange
+	tree, err := NewConfigReader().AddString("name = x\nids[] = 1\nlimits[a] = 1", FtEnv, "a").ReadTree()
+	require.Nil(t, err)
+
+	// Act
+	_, errMissing := tree.GetString("missing")
+	_, errInt := tree.GetInt("name")
+	_, errDuration := tree.GetDuration("name")
+	_, errCollection := tree.GetString("ids")
+	_, errMap := tree.GetStringSlice("limits")
+
+	// Assert
+	require.EqualError(t, errMissing, "key missing not found")
+	require.Contains(t, errInt.Error(), "can't convert value of key name to int")
+	require.Contains(t, errDuration.Error(), "can't convert value of key name to time.Duration")
+	require.EqualError(t, errCollection, "key ids is a collection")
+	require.EqualError(t, errMap, "key limits is a map")
+}
+
+func Test_Tree_Map(t *testing.T) {
+	// Arrange
+	tree, err := NewConfigReader().
+		AddString("name = x\nids[] = 1\n", FtEnv, "a").
+		AddString(`{"db": {"port": 5432, "on": true, "tags": {"a": "x"}, "n": null}, "list": [1.5]}`, FtJson, "b").
+		ReadTree()
+	require.Nil(t, err)
+
+	// Act
+	result := tree.Map()
+
+	// Assert
+	require.Equal(t, map[string]interface{}{
+		"name": "x",
+		"ids":  []interface{}{"1"},
+		"list": []interface{}{1.5},
+		"db": map[string]interface{}{
+			"port": float64(5432),
+			"on":   true,
+			"tags": map[string]interface{}{"a": "x"},
+			"n":    nil,
+		},
+	}, result)
+}
@@ -5,22 +5,54 @@ import (
 )
 
 type configReader struct {
-	sources []configSource
-	options ConfigOptions
-	data    configData
+	sources    []configSource
+	options    ConfigOptions
+	data       configData
+	bindings   []binding
+	migrations []migration
+}
+type binding struct {
+	prefix string
+	target interface{}
 }
 type configSource struct {
-	name     string
-	value    string
-	ft       formatType
-	fromFile bool
+	name        string
+	value       string
+	ft          formatType
+	fromFile    bool
+	withSubdirs bool
+	priority    int         // sources with higher priority are read later
+	layer       string      // layer name shown by Explain
+	atPrefix    string      // prefix added to every key of the source
+	stripPrefix string      // prefix removed from every key of the source
+	structValue interface{} // pointer to the struct of AddStruct source
 }
 type configData struct {
 	currentLine int
 	currentPos  int
 	currentFile string
+	keyLine     int
 
 	initErrors []string
+
+	lastTree    intermediateTree
+	lastSources []configSource
+
+	trackUnknownKeys bool
+	unknownKeys      []unknownKey
+
+	schemaless bool // read all keys without the user config struct
+
+	currentFormat formatType // format of the source being read
+	atPrefix      string     // AtPrefix of the source being read
+	stripPrefix   string     // StripPrefix of the source being read
+
+	warnings []configWarning
+}
+type unknownKey struct {
+	name   string
+	source string
+	line   int
 }
 
 type Parser func(string) (interface{}, error)
@@ -30,6 +62,24 @@ type ConfigOptions struct {
 	RewriteValues bool
 	// Custom parsers for user types (key - parser name, value - parser)
 	Parsers map[string]Parser
+	// Max size of a secret file in bytes, default is 64 KiB
+	SecretFileMaxSize int64
+	// Refuse world-readable secret files, default is false
+	StrictSecretFiles bool
+	// Show a hash prefix instead of "******" for secret values, default is false
+	RedactWithHash bool
+	// Return an error for keys in files and strings which no struct field claims, default is false
+	Strict bool
+	// Logger for warnings, e.g. about deprecated keys, default is nil
+	Logger Logger
+	// Key names of fields without env tag, default is NamingLower
+	Naming namingType
+	// Use json and yaml tags of fields without env tag, default is false
+	FallbackTags bool
+	// Match key names, map keys and environment variables case sensitively, default is false
+	CaseSensitive bool
+	// Keep the values of the user config struct set before reading as the lowest priority source, default is false
+	PreserveValues bool
 }
 
 type intermediateTree map[string][]intermediateData
@@ -37,26 +87,56 @@ type intermediateData struct {
 	source    int
 	value     interface{}
 	valueType valueType
+	ref       string //reference the value was resolved from, e.g. secret file
+	line      int
 }
 
 type formatType int
+type namingType int
 type valueType int
 
 type structInfo struct {
-	fieldName  string
-	fieldType  reflect.Type
-	field      reflect.Value
+	fieldName   string
+	fieldType   reflect.Type
+	field       reflect.Value
+	keyName     string
+	defValue    string
+	isRequired  bool
+	useParser   bool
+	separator   string
+	separator2  string //map separator
+	isSlice     bool
+	isMap       bool
+	isPointer   bool
+	merge       string // merge strategy of collections from different sources
+	size        int
+	secretFile  bool
+	isSecret    bool
+	description string
+	isRemain    bool // gets all unclaimed keys under the parent prefix
+	isRaw       bool // RawSection or json.RawMessage, keeps the whole section as text
+	aliases     []string
+	deprecated  bool
+}
+
+// Value of the unclaimed key for the field with "remain" option
+type remainItem struct {
+	values    []string
+	valueType valueType
+	isSlice   bool
+}
+
+type tagData struct {
 	keyName    string
-	defValue   string
 	isRequired bool
-	useParser  bool
-	separator  string
-	separator2 string //map separator
-	isSlice    bool
-	isMap      bool
-	isPointer  bool
 	append     bool
-	size       int
+	useParser  bool
+	secretFile bool
+	isSecret   bool
+	remain     bool
+	aliases    []string
+	deprecated bool
+	squash     bool
 }
 
 type jsonTempData struct {
@@ -0,0 +1,110 @@
+package configuration
+
+import (
+	"strings"
+)
+
+// Remove the values of earlier sources marked with "*unset" in later ones
+// "key = *unset" removes the key, "key[k] = *unset" removes the map entry,
+// "key[] = *unset:v" removes the slice item, the markers themselves are never values
+// values of struct sources are never markers
+func (cr *configReader) applyUnset(it intermediateTree) {
+	for name, data := range it {
+		result := make([]intermediateData, 0, len(data))
+		for _, d := range data {
+			if cr.isStructSource(d.source) {
+				result = append(result, d)
+				continue
+			}
+			switch value := d.value.(type) {
+			case string:
+				if value == unsetValue && d.valueType != vtNull {
+					result = result[:0]
+					continue
+				}
+			case []string:
+				items := make([]string, 0, len(value))
+				marked := false
+				for _, v := range value {
+					if v == unsetValue {
+						result = result[:0]
+						marked = true
+					} else if item, ok := strings.CutPrefix(v, unsetItemPrefix); ok {
+						result = removeUnsetItem(result, item)
+						marked = true
+					} else {
+						items = append(items, v)
+					}
+				}
+				if marked && len(items) == 0 {
+					continue
+				}
+				d.value = items
+			case map[string]string:
+				marked := d.valueType == vtUnset
+				if marked {
+					result = result[:0]
+					d.valueType = vtAny
+				}
+				items := make(map[string]string, len(value))
+				for k, v := range value {
+					if v == unsetValue {
+						result = removeUnsetKey(result, k)
+						marked = true
+					} else {
+						items[k] = v
+					}
+				}
+				if marked && len(items) == 0 {
+					continue
+				}
+				d.value = items
+			}
+			result = append(result, d)
+		}
+
+		if len(result) == 0 {
+			delete(it, name)
+		} else {
+			it[name] = result
+		}
+	}
+}
+
+func removeUnsetItem(data []intermediateData, item string) []intermediateData {
+	for i, d := range data {
+		values, ok := d.value.([]string)
+		if !ok {
+			continue
+		}
+		items := make([]string, 0, len(values))
+		for _, v := range values {
+			if v != item {
+				items = append(items, v)
+			}
+		}
+		data[i].value = items
+	}
+	return data
+}
+
+func removeUnsetKey(data []intermediateData, key string) []intermediateData {
+	for i, d := range data {
+		values, ok := d.value.(map[string]string)
+		if !ok {
+			continue
+		}
+		items := make(map[string]string, len(values))
+		for k, v := range values {
+			if k != key {
+				items[k] = v
+			}
+		}
+		data[i].value = items
+	}
+	return data
+}
+
+func isUnsetValue(value string) bool {
+	return value == unsetValue || strings.HasPrefix(value, unsetItemPrefix)
+}
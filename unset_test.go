@@ -0,0 +1,77 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReadConfig_unset_success(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Host    string            `env:"host" def:"localhost"`
+		Port    int               `env:"port"`
+		Hosts   []string          `env:"hosts" merge:"append"`
+		Ports   []int             `env:"ports" merge:"append"`
+		Tags    []string          `env:"tags"`
+		Limits  map[string]int    `env:"limits" merge:"deep"`
+		Labels  map[string]string `env:"labels" merge:"deep"`
+		Options map[string]string `env:"options"`
+	}{}
+	base := "host = h\nport = 80\nhosts = a,b,c\nports = 1,2\ntags = x,y\n" +
+		"limits[foo] = 1\nlimits[bar] = 2\nlabels = a:1,b:2\noptions = a:1"
+	site := "host = *unset\nhosts[] = *unset:b\nhosts[] = d\ntags[] = *unset:y\n" +
+		"limits[foo] = *unset\nlabels = *unset\nlabels[c] = 3\n"
+	host := `{"port": "*unset", "ports": [3, "*unset:1"], "options": "*unset"}`
+
+	// Act
+	err := NewConfigReader().
+		AddString(base, FtEnv, "base").
+		AddString(site, FtIni, "site").
+		AddString(host, FtJson, "host").
+		ReadConfig(config)
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, "localhost", config.Host)
+	require.Equal(t, 0, config.Port)
+	require.Equal(t, []string{"a", "c", "d"}, config.Hosts)
+	require.Equal(t, []int{2, 3}, config.Ports)
+	require.Equal(t, []string{"x"}, config.Tags)
+	require.Equal(t, map[string]int{"bar": 2}, config.Limits)
+	require.Equal(t, map[string]string{"c": "3"}, config.Labels)
+	require.Nil(t, config.Options)
+}
+
+func Test_ReadConfig_unset_required(t *testing.T) {
+	// Arrange
+	config := &struct {
+		Host string `env:"host,required"`
+	}{}
+
+	// Act
+	err := NewConfigReader().
+		AddString("host = h", FtEnv, "base").
+		AddString("host = *unset", FtEnv, "site").
+		ReadConfig(config)
+
+	// Assert
+	require.EqualError(t, err, "required field Host value is missing")
+}
+
+func Test_ReadTree_unset(t *testing.T) {
+	// Arrange
+	cr := NewConfigReader().
+		AddString("host = h\nport = 80\nlimits[a] = 1\nlimits[b] = 2", FtEnv, "base").
+		AddString("limits[a] = *unset", FtIni, "site").
+		AddString(`{"port": "*unset"}`, FtJson, "host")
+
+	// Act
+	tree, err := cr.ReadTree()
+
+	// Assert
+	require.Nil(t, err)
+	require.Equal(t, []string{"host", "limits"}, tree.Keys(""))
+	limits, _ := tree.Get("limits")
+	require.Equal(t, map[string]string{"b": "2"}, limits)
+}
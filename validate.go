@@ -0,0 +1,430 @@
+package configuration
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type schemaRule struct {
+	types            []string
+	enum             []interface{}
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	minLength        *int
+	maxLength        *int
+	format           string
+	minItems         *int
+	maxItems         *int
+	items            *schemaRule // array items
+	values           *schemaRule // map values
+}
+
+type schemaObjectRule struct {
+	required []string
+	closed   bool // additionalProperties is false
+}
+
+type schemaRules struct {
+	si      []structInfo
+	keys    map[string]*schemaRule
+	objects map[string]schemaObjectRule
+}
+
+// Validate the configuration file against the JSON Schema without a Go struct
+// source - relative or absolute path to the env, ini or json file
+// schema - JSON Schema, e.g. generated by JSONSchema
+func ValidateAgainstSchema(source string, schema []byte) error {
+	rules, err := parseSchemaRules(schema)
+	if err != nil {
+		return err
+	}
+
+	cr := NewConfigReader(source)
+	if errs := cr.GetErrors(); errs != nil {
+		return errors.Join(errs...)
+	}
+	cr.data.trackUnknownKeys = true
+
+	it := intermediateTree{}
+	err = cr.readSources(it, rules.si)
+	if err != nil {
+		return err
+	}
+
+	return rules.validate(it, cr.data.unknownKeys, source)
+}
+
+func parseSchemaRules(schema []byte) (*schemaRules, error) {
+	decoder := json.NewDecoder(bytes.NewReader(schema))
+	decoder.UseNumber()
+	root := map[string]interface{}{}
+	if err := decoder.Decode(&root); err != nil {
+		return nil, errors.New("invalid schema: " + err.Error())
+	}
+
+	rules := &schemaRules{keys: map[string]*schemaRule{}, objects: map[string]schemaObjectRule{}}
+	err := rules.addObject("", root)
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (rules *schemaRules) addObject(prefix string, schema map[string]interface{}) error {
+	object := schemaObjectRule{closed: schema["additionalProperties"] == false}
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, r := range required {
+			name, ok := r.(string)
+			if !ok {
+				return errors.New("invalid schema: required must contain strings")
+			}
+			object.required = append(object.required, strings.ToLower(prefix+name))
+		}
+	}
+	rules.objects[strings.TrimSuffix(prefix, ".")] = object
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		key := strings.ToLower(prefix + name)
+		property, ok := properties[name].(map[string]interface{})
+		if !ok {
+			return errors.New("invalid schema for key " + key)
+		}
+		if _, ok := property["properties"]; ok {
+			if err := rules.addObject(key+".", property); err != nil {
+				return err
+			}
+			continue
+		}
+
+		rule, err := parseSchemaRule(key, property)
+		if err != nil {
+			return err
+		}
+		rules.keys[key] = rule
+		rules.si = append(rules.si, structInfo{
+			fieldName:  key,
+			keyName:    key,
+			separator:  sepDefault,
+			separator2: sep2Default,
+			isSlice:    slices.Contains(rule.types, "array"),
+			isMap:      slices.Contains(rule.types, "object"),
+		})
+	}
+	return nil
+}
+
+func parseSchemaRule(key string, schema map[string]interface{}) (*schemaRule, error) {
+	invalid := errors.New("invalid schema for key " + key)
+	rule := &schemaRule{}
+
+	switch t := schema["type"].(type) {
+	case string:
+		rule.types = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			s, ok := item.(string)
+			if !ok {
+				return nil, invalid
+			}
+			rule.types = append(rule.types, s)
+		}
+	case nil:
+	default:
+		return nil, invalid
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		rule.enum = enum
+	} else if c, ok := schema["const"]; ok {
+		rule.enum = []interface{}{c}
+	}
+	rule.format, _ = schema["format"].(string)
+
+	var err error
+	numbers := map[string]**float64{
+		"minimum":          &rule.minimum,
+		"maximum":          &rule.maximum,
+		"exclusiveMinimum": &rule.exclusiveMinimum,
+		"exclusiveMaximum": &rule.exclusiveMaximum,
+	}
+	for name, target := range numbers {
+		if *target, err = schemaNumber(schema, name); err != nil {
+			return nil, invalid
+		}
+	}
+	counts := map[string]**int{
+		"minLength": &rule.minLength,
+		"maxLength": &rule.maxLength,
+		"minItems":  &rule.minItems,
+		"maxItems":  &rule.maxItems,
+	}
+	for name, target := range counts {
+		n, err := schemaNumber(schema, name)
+		if err != nil {
+			return nil, invalid
+		}
+		if n != nil {
+			count := int(*n)
+			*target = &count
+		}
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		if rule.items, err = parseSchemaRule(key, items); err != nil {
+			return nil, err
+		}
+	}
+	if values, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+		if rule.values, err = parseSchemaRule(key, values); err != nil {
+			return nil, err
+		}
+	}
+	return rule, nil
+}
+
+func schemaNumber(schema map[string]interface{}, name string) (*float64, error) {
+	value, ok := schema[name]
+	if !ok {
+		return nil, nil
+	}
+	number, ok := value.(json.Number)
+	if !ok {
+		return nil, errors.New("not a number")
+	}
+	f, err := number.Float64()
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (rules *schemaRules) validate(it intermediateTree, unknownKeys []unknownKey, source string) error {
+	type lineError struct {
+		line    int
+		message string
+	}
+	lineErrors := []lineError{}
+
+	for _, k := range unknownKeys {
+		if rules.objects[rules.closestObject(k.name)].closed {
+			lineErrors = append(lineErrors, lineError{k.line, "unknown key \"" + k.name + "\""})
+		}
+	}
+
+	for _, info := range rules.si {
+		rule := rules.keys[info.keyName]
+		for _, d := range it[info.keyName] {
+			for _, message := range rule.validate(d) {
+				lineErrors = append(lineErrors, lineError{d.line, "key \"" + info.keyName + "\" " + message})
+			}
+		}
+	}
+	sort.SliceStable(lineErrors, func(i, j int) bool { return lineErrors[i].line < lineErrors[j].line })
+
+	errs := make([]error, 0, len(lineErrors))
+	for _, e := range lineErrors {
+		errs = append(errs, errors.New(source+":"+strconv.Itoa(e.line)+": "+e.message))
+	}
+
+	objects := make([]string, 0, len(rules.objects))
+	for name := range rules.objects {
+		objects = append(objects, name)
+	}
+	sort.Strings(objects)
+	for _, name := range objects {
+		if name != "" && !hasTreeKey(it, name) {
+			continue
+		}
+		for _, key := range rules.objects[name].required {
+			if !hasTreeKey(it, key) {
+				errs = append(errs, errors.New(source+": required key \""+key+"\" is missing"))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Find the nearest schema object which contains the key
+func (rules *schemaRules) closestObject(key string) string {
+	for {
+		index := strings.LastIndex(key, ".")
+		if index < 0 {
+			return ""
+		}
+		key = key[:index]
+		if _, ok := rules.objects[key]; ok {
+			return key
+		}
+	}
+}
+
+func hasTreeKey(it intermediateTree, name string) bool {
+	for key, data := range it {
+		if (key == name || strings.HasPrefix(key, name+".")) && len(data) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (rule *schemaRule) validate(d intermediateData) []string {
+	switch value := d.value.(type) {
+	case string:
+		if message := rule.validateValue(value, d.valueType); message != "" {
+			return []string{message}
+		}
+	case []string:
+		if len(value) == 1 && value[0] == nilDefault && (d.valueType == vtNull || d.valueType == vtAny) &&
+			slices.Contains(rule.types, "null") {
+			return nil
+		}
+		messages := []string{}
+		if !rule.allows("array") {
+			messages = append(messages, "must be "+strings.Join(rule.types, " or "))
+		}
+		if rule.minItems != nil && len(value) < *rule.minItems {
+			messages = append(messages, "must have at least "+strconv.Itoa(*rule.minItems)+" items")
+		}
+		if rule.maxItems != nil && len(value) > *rule.maxItems {
+			messages = append(messages, "must have at most "+strconv.Itoa(*rule.maxItems)+" items")
+		}
+		if rule.items != nil {
+			for i, v := range value {
+				if message := rule.items.validateValue(v, d.valueType); message != "" {
+					messages = append(messages, "item "+strconv.Itoa(i)+" "+message)
+				}
+			}
+		}
+		return messages
+	case map[string]string:
+		messages := []string{}
+		if !rule.allows("object") {
+			messages = append(messages, "must be "+strings.Join(rule.types, " or "))
+		}
+		if rule.values != nil {
+			for _, k := range sortedMapKeys(value) {
+				if message := rule.values.validateValue(value[k], d.valueType); message != "" {
+					messages = append(messages, "item \""+k+"\" "+message)
+				}
+			}
+		}
+		return messages
+	}
+	return nil
+}
+
+func (rule *schemaRule) allows(t string) bool {
+	return len(rule.types) == 0 || slices.Contains(rule.types, t)
+}
+
+// Check the scalar value, returns the error message or empty string
+func (rule *schemaRule) validateValue(value string, vType valueType) string {
+	isNull := vType == vtNull || vType == vtAny && value == nilDefault
+	if isNull && rule.allows("null") {
+		return ""
+	}
+
+	matched := ""
+	for _, t := range rule.types {
+		if matchesSchemaType(t, value, vType) {
+			matched = t
+			break
+		}
+	}
+	if len(rule.types) > 0 && matched == "" {
+		return "must be " + strings.Join(rule.types, " or ")
+	}
+
+	if len(rule.enum) > 0 && !slices.ContainsFunc(rule.enum, func(e interface{}) bool { return matchesEnum(e, value, isNull) }) {
+		return "must be one of the allowed values"
+	}
+
+	if number, err := strconv.ParseFloat(value, 64); err == nil && (vType == vtNumber || matched == "integer" || matched == "number") {
+		if rule.minimum != nil && number < *rule.minimum {
+			return "must be >= " + formatFloat(*rule.minimum)
+		}
+		if rule.maximum != nil && number > *rule.maximum {
+			return "must be <= " + formatFloat(*rule.maximum)
+		}
+		if rule.exclusiveMinimum != nil && number <= *rule.exclusiveMinimum {
+			return "must be > " + formatFloat(*rule.exclusiveMinimum)
+		}
+		if rule.exclusiveMaximum != nil && number >= *rule.exclusiveMaximum {
+			return "must be < " + formatFloat(*rule.exclusiveMaximum)
+		}
+	}
+
+	if matched == "string" || vType == vtString {
+		length := utf8.RuneCountInString(value)
+		if rule.minLength != nil && length < *rule.minLength {
+			return "must be at least " + strconv.Itoa(*rule.minLength) + " characters long"
+		}
+		if rule.maxLength != nil && length > *rule.maxLength {
+			return "must be at most " + strconv.Itoa(*rule.maxLength) + " characters long"
+		}
+		if rule.format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, value); err != nil {
+				return "must be date-time"
+			}
+		}
+	}
+	return ""
+}
+
+func matchesSchemaType(t, value string, vType valueType) bool {
+	switch t {
+	case "null":
+		return vType == vtNull || vType == vtAny && value == nilDefault
+	case "string":
+		return vType == vtString || vType == vtAny
+	case "integer":
+		number, err := strconv.ParseFloat(value, 64)
+		return (vType == vtNumber || vType == vtAny) && err == nil && number == math.Trunc(number)
+	case "number":
+		_, err := strconv.ParseFloat(value, 64)
+		return (vType == vtNumber || vType == vtAny) && err == nil
+	case "boolean":
+		_, err := strconv.ParseBool(value)
+		return vType == vtBool || vType == vtAny && err == nil
+	}
+	return false
+}
+
+func matchesEnum(e interface{}, value string, isNull bool) bool {
+	switch e := e.(type) {
+	case nil:
+		return isNull
+	case string:
+		return !isNull && e == value
+	case json.Number:
+		expected, err1 := e.Float64()
+		actual, err2 := strconv.ParseFloat(value, 64)
+		return err1 == nil && err2 == nil && expected == actual
+	case bool:
+		actual, err := strconv.ParseBool(value)
+		return err == nil && e == actual
+	}
+	return false
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,121 @@
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testValidateSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"properties": {
+		"host": {"type": "string", "minLength": 1},
+		"port": {"type": "integer", "minimum": 1, "maximum": 65535},
+		"mode": {"type": "string", "enum": ["dev", "prod"]},
+		"ratio": {"type": ["number", "null"]},
+		"hosts": {"type": "array", "items": {"type": "string"}, "maxItems": 2},
+		"limits": {"type": "object", "additionalProperties": {"type": "integer"}},
+		"db": {
+			"type": "object",
+			"properties": {"user": {"type": "string"}, "debug": {"type": "boolean"}},
+			"required": ["user"],
+			"additionalProperties": false
+		}
+	},
+	"required": ["host", "port"],
+	"additionalProperties": false
+}`
+
+func writeValidateFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.Nil(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+type testCaseValidate struct {
+	name    string
+	content string
+	errs    []string
+}
+
+func Test_ValidateAgainstSchema_cases(t *testing.T) {
+	// Arrange
+	cases := []testCaseValidate{
+		{"ok.env", "host = h\nport = 80\nmode = dev\nratio = *nil\nhosts = a,b\nlimits = a:1\ndb.user = u\n", nil},
+		{"ok.ini", "host = h\nport = 80\n[db]\nuser = u\ndebug = true\n", nil},
+		{"ok.json", `{"host": "h", "port": 80, "ratio": null, "hosts": ["a"], "limits": {"a": 1}, "db": {"user": "u"}}`, nil},
+		{"types.env", "host = h\nport = 8.5\nmode = test\nhosts = a,b,c\nlimits = a:x\n", []string{
+			":2: key \"port\" must be integer", ":3: key \"mode\" must be one of the allowed values",
+			":4: key \"hosts\" must have at most 2 items", ":5: key \"limits\" item \"a\" must be integer"}},
+		{"types.json", "{\n\"host\": \"h\",\n\"port\": \"80\",\n\"ratio\": true\n}", []string{
+			":3: key \"port\" must be integer", ":4: key \"ratio\" must be number or null"}},
+		{"range.ini", "host =\nport = 0\n", []string{
+			":1: key \"host\" must be at least 1 characters long", ":2: key \"port\" must be >= 1"}},
+		{"unknown.ini", "host = h\nport = 1\nextra = 1\n[db]\nuser = u\nname = x\n", []string{
+			":3: unknown key \"extra\"", ":6: unknown key \"db.name\""}},
+		{"unknown.json", "{\"host\": \"h\", \"port\": 1,\n\"other\": {\"a\": 1},\n\"db\": {\"user\": \"u\",\n\"name\": \"x\"}}", []string{
+			":2: unknown key \"other\"", ":4: unknown key \"db.name\""}},
+		{"required.env", "db.debug = false\n", []string{
+			": required key \"host\" is missing", ": required key \"port\" is missing", ": required key \"db.user\" is missing"}},
+	}
+
+	// Act & Assert
+	for i, c := range cases {
+		t.Log("Test case:", i)
+		test_ValidateAgainstSchema(t, c)
+	}
+}
+
+func test_ValidateAgainstSchema(t *testing.T, testCase testCaseValidate) {
+	// Arrange
+	path := writeValidateFile(t, testCase.name, testCase.content)
+
+	// Act
+	err := ValidateAgainstSchema(path, []byte(testValidateSchema))
+
+	// Assert
+	if testCase.errs == nil {
+		require.Nil(t, err)
+		return
+	}
+	require.NotNil(t, err)
+	for _, e := range testCase.errs {
+		require.Contains(t, err.Error(), path+e)
+	}
+}
+
+func Test_ValidateAgainstSchema_generatedSchema(t *testing.T) {
+	// Arrange
+	schema, err := JSONSchema(&struct {
+		Host  string   `env:"host,required"`
+		Ports []uint16 `env:"ports"`
+	}{})
+	require.Nil(t, err)
+	path := writeValidateFile(t, "config.env", "ports = 80,70000\n")
+
+	// Act
+	err = ValidateAgainstSchema(path, schema)
+
+	// Assert
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), path+":1: key \"ports\" item 1 must be <= 65535")
+	require.Contains(t, err.Error(), path+": required key \"host\" is missing")
+}
+
+func Test_ValidateAgainstSchema_error_cases(t *testing.T) {
+	// Arrange
+	path := writeValidateFile(t, "config.json", "{\"host\": }")
+
+	// Act
+	errSyntax := ValidateAgainstSchema(path, []byte(testValidateSchema))
+	errSchema := ValidateAgainstSchema(path, []byte("{"))
+	errType := ValidateAgainstSchema("config.txt", []byte(testValidateSchema))
+
+	// Assert
+	require.NotNil(t, errSyntax)
+	require.NotNil(t, errSchema)
+	require.NotNil(t, errType)
+}